@@ -0,0 +1,381 @@
+// Package breaker provides a circuit breaker whose failures are classified by the status codes of
+// domainerr errors, so that client faults, e.g., InvalidArgument, don't trip it.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// StateClosed lets all calls through, and counts the consecutive failures.
+	StateClosed State = iota
+	// StateOpen rejects all calls until the open timeout elapses.
+	StateOpen
+	// StateHalfOpen lets a limited number of trial calls through. The breaker is closed if they
+	// all succeed, or opened again on the first failure. A trial call not finished within the open
+	// timeout counts as a failure.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// Transition is a state transition of a Breaker.
+type Transition struct {
+	Name string
+	From State
+	To   State
+	At   time.Time
+}
+
+// failureCodes are the codes of transient faults of the callee or its dependencies.
+var failureCodes = map[domainerr.Code]bool{
+	domainerr.CodeUnavailable:      true,
+	domainerr.CodeDeadlineExceeded: true,
+	domainerr.CodeInternalError:    true,
+	domainerr.CodeUnknown:          true,
+}
+
+// IsFailure is the default failure predicate of a Breaker. Only errors with the status codes
+// ServiceUnavailable, DeadlineExceeded, InternalError and UnknownError count as failures, and so
+// do errors whose specific cases override their fault to domainerr.DependencyFault. Permanent
+// faults, e.g., Unimplemented, don't, because opening the breaker doesn't help the callee recover.
+// An error that isn't a *domainerr.Error is handled as an UnknownError, except context.Canceled,
+// which is caused by the caller. A Breaker counts a context.Canceled that isn't a failure as
+// neither a success nor a failure.
+func IsFailure(err error) bool {
+	if domainerr.IsNil(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if domainerr.FaultOf(err) == domainerr.DependencyFault {
+		return true
+	}
+	var domainErr *domainerr.Error
+	if !errors.As(err, &domainErr) || domainerr.IsNil(domainErr) {
+		return true
+	}
+	return failureCodes[domainErr.Status().Code()]
+}
+
+// Breaker is a circuit breaker. It opens after a number of consecutive failures, rejects calls
+// with StatusUnavailable errors while open, and lets trial calls through once the open timeout
+// elapses. It is safe for concurrent use.
+type Breaker struct {
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenMaxCalls int
+	isFailure        func(err error) bool
+	onStateChange    func(t Transition)
+	now              func() time.Time
+
+	mu              sync.Mutex
+	state           State
+	failures        int
+	openedAt        time.Time
+	halfOpenSuccess int
+	// trials are the start times of the half-open trial calls in flight, by their IDs.
+	trials    map[uint64]time.Time
+	nextTrial uint64
+	// generation is increased on every transition, so that the results of the calls allowed
+	// before it are ignored.
+	generation uint64
+}
+
+type Opt func(b *Breaker)
+
+// WithFailureThreshold sets the number of consecutive failures which opens a Breaker. The default
+// is 5.
+func WithFailureThreshold(n int) Opt {
+	return func(b *Breaker) {
+		b.failureThreshold = n
+	}
+}
+
+// WithOpenTimeout sets how long a Breaker stays open before letting trial calls through. The
+// default is 30s.
+func WithOpenTimeout(d time.Duration) Opt {
+	return func(b *Breaker) {
+		b.openTimeout = d
+	}
+}
+
+// WithHalfOpenMaxCalls sets the number of trial calls which must succeed to close a half-open
+// Breaker. The default is 1.
+func WithHalfOpenMaxCalls(n int) Opt {
+	return func(b *Breaker) {
+		b.halfOpenMaxCalls = n
+	}
+}
+
+// WithFailurePredicate replaces IsFailure as the failure predicate of a Breaker.
+func WithFailurePredicate(isFailure func(err error) bool) Opt {
+	return func(b *Breaker) {
+		b.isFailure = isFailure
+	}
+}
+
+// WithStateChangeHook sets the function called on every state transition of a Breaker, e.g., to
+// log it or to record it in metrics. It is called without holding the lock of the Breaker.
+func WithStateChangeHook(hook func(t Transition)) Opt {
+	return func(b *Breaker) {
+		b.onStateChange = hook
+	}
+}
+
+// WithClock sets the function returning the current time, which is time.Now by default. It lets
+// tests control the time.
+func WithClock(now func() time.Time) Opt {
+	return func(b *Breaker) {
+		b.now = now
+	}
+}
+
+// New creates a closed Breaker with the given name, which appears in its errors and transitions.
+func New(name string, opts ...Opt) (*Breaker, error) {
+	b := &Breaker{
+		name:             name,
+		failureThreshold: 5,
+		openTimeout:      30 * time.Second,
+		halfOpenMaxCalls: 1,
+		isFailure:        IsFailure,
+		now:              time.Now,
+	}
+	for _, setOpt := range opts {
+		setOpt(b)
+	}
+	err := domainerr.CheckArgument(b.failureThreshold > 0, "failure threshold <= 0")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(b.openTimeout > 0, "open timeout <= 0")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(b.halfOpenMaxCalls > 0, "half-open max calls <= 0")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(b.isFailure != nil, "failure predicate is nil")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(b.now != nil, "clock is nil")
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state. An open Breaker whose open timeout has elapsed is reported as
+// half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	t := b.refresh()
+	s := b.state
+	b.mu.Unlock()
+	b.report(t)
+	return s
+}
+
+// Do calls call if the Breaker allows it, and records the result. If the Breaker is open, call
+// isn't called, and a StatusUnavailable *domainerr.Error is returned, whose details are an
+// errdetails.RetryInfo with the remaining open time as the retry delay. If call panics, the panic
+// is recorded as a failure and propagated.
+func (b *Breaker) Do(call func() error) error {
+	gen, trial, err := b.allow()
+	if err != nil {
+		return err
+	}
+	finished := false
+	defer func() {
+		if !finished {
+			b.record(gen, trial, failure)
+		}
+	}()
+	err = call()
+	finished = true
+	b.record(gen, trial, b.outcomeOf(err))
+	return err
+}
+
+// Allow checks whether a call is allowed. If so, the returned function must be called with the
+// result of the call, and only its first call counts. A half-open trial call whose result isn't
+// recorded within the open timeout counts as a failure. If the call isn't allowed, the returned
+// error is the one documented in Do.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	gen, trial, err := b.allow()
+	if err != nil {
+		return nil, err
+	}
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() {
+			b.record(gen, trial, b.outcomeOf(err))
+		})
+	}, nil
+}
+
+// allow returns the generation and the trial ID of an allowed call.
+func (b *Breaker) allow() (gen uint64, trial uint64, err error) {
+	b.mu.Lock()
+	t := b.refresh()
+	gen = b.generation
+	var delay time.Duration
+	switch b.state {
+	case StateOpen:
+		delay = b.openTimeout - b.now().Sub(b.openedAt)
+	case StateHalfOpen:
+		if len(b.trials)+b.halfOpenSuccess >= b.halfOpenMaxCalls {
+			delay = b.openTimeout
+		} else {
+			b.nextTrial++
+			trial = b.nextTrial
+			if b.trials == nil {
+				b.trials = make(map[uint64]time.Time)
+			}
+			b.trials[trial] = b.now()
+		}
+	}
+	b.mu.Unlock()
+	b.report(t)
+
+	if delay > 0 {
+		return 0, 0, b.openError(delay)
+	}
+	return gen, trial, nil
+}
+
+type outcome int
+
+const (
+	success outcome = iota
+	failure
+	// neutral is the outcome of a call canceled by the caller, which tells nothing about the
+	// health of the callee.
+	neutral
+)
+
+func (b *Breaker) outcomeOf(err error) outcome {
+	if b.isFailure(err) {
+		return failure
+	}
+	if errors.Is(err, context.Canceled) {
+		return neutral
+	}
+	return success
+}
+
+func (b *Breaker) record(gen uint64, trial uint64, o outcome) {
+	b.mu.Lock()
+	if gen != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	var t *Transition
+	switch b.state {
+	case StateClosed:
+		switch o {
+		case success:
+			b.failures = 0
+		case failure:
+			if b.failures++; b.failures >= b.failureThreshold {
+				t = b.transit(StateOpen)
+			}
+		}
+	case StateHalfOpen:
+		delete(b.trials, trial)
+		switch o {
+		case success:
+			if b.halfOpenSuccess++; b.halfOpenSuccess >= b.halfOpenMaxCalls {
+				t = b.transit(StateClosed)
+			}
+		case failure:
+			t = b.transit(StateOpen)
+		}
+	}
+	b.mu.Unlock()
+	b.report(t)
+}
+
+// refresh moves an open Breaker to half-open if its open timeout has elapsed, and a half-open
+// Breaker back to open if a trial call hasn't finished within the open timeout. It must be called
+// with the lock held.
+func (b *Breaker) refresh() *Transition {
+	now := b.now()
+	switch b.state {
+	case StateOpen:
+		if now.Sub(b.openedAt) >= b.openTimeout {
+			return b.transit(StateHalfOpen)
+		}
+	case StateHalfOpen:
+		for _, start := range b.trials {
+			if now.Sub(start) >= b.openTimeout {
+				return b.transit(StateOpen)
+			}
+		}
+	}
+	return nil
+}
+
+// transit must be called with the lock held.
+func (b *Breaker) transit(to State) *Transition {
+	t := &Transition{Name: b.name, From: b.state, To: to, At: b.now()}
+	b.state = to
+	b.generation++
+	b.failures = 0
+	b.trials = nil
+	b.halfOpenSuccess = 0
+	if to == StateOpen {
+		b.openedAt = t.At
+	}
+	return t
+}
+
+func (b *Breaker) report(t *Transition) {
+	if t != nil && b.onStateChange != nil {
+		b.onStateChange(*t)
+	}
+}
+
+func (b *Breaker) openError(delay time.Duration) error {
+	return domainerr.NewUnavailable().
+		WithMessagef("circuit breaker %s is open", b.name).
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}).
+		Build()
+}
+
+// RetryDelayOf returns the retry delay in the errdetails.RetryInfo of the given error, e.g., the
+// one returned by an open Breaker.
+func RetryDelayOf(err error) (time.Duration, bool) {
+	info, found := domainerr.FindDetail[*errdetails.RetryInfo](err)
+	if !found || info.GetRetryDelay() == nil {
+		return 0, false
+	}
+	return info.GetRetryDelay().AsDuration(), true
+}
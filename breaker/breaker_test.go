@@ -0,0 +1,246 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/ikonglong/domainerr/internal/fakeclock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBreaker(t *testing.T, opts ...Opt) (*Breaker, *fakeclock.Clock, *[]Transition) {
+	clock := fakeclock.New()
+	var transitions []Transition
+	opts = append([]Opt{
+		WithFailureThreshold(2),
+		WithOpenTimeout(10 * time.Second),
+		WithClock(clock.Now),
+		WithStateChangeHook(func(t Transition) {
+			transitions = append(transitions, t)
+		}),
+	}, opts...)
+	b, err := New("user-service", opts...)
+	assert.Nil(t, err)
+	return b, clock, &transitions
+}
+
+func fail(code domainerr.Code) func() error {
+	return func() error {
+		return domainerr.NewWithStatus(domainerr.NewWithCode(code)).Build()
+	}
+}
+
+func succeed() error {
+	return nil
+}
+
+// gatewayRejected is a FailedPrecondition case caused by a dependency.
+type gatewayRejected struct{}
+
+func (gatewayRejected) Identifier() string {
+	return "payment.gateway_rejected"
+}
+
+func (gatewayRejected) StatusCode() domainerr.Code {
+	return domainerr.CodeFailedPrecondition
+}
+
+func (gatewayRejected) Fault() domainerr.Fault {
+	return domainerr.DependencyFault
+}
+
+func TestIsFailure(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{fail(domainerr.CodeInvalidArgument)(), false},
+		{fail(domainerr.CodeNotFound)(), false},
+		{fail(domainerr.CodeUnavailable)(), true},
+		{fail(domainerr.CodeDeadlineExceeded)(), true},
+		{fail(domainerr.CodeInternalError)(), true},
+		{fail(domainerr.CodeUnknown)(), true},
+		{errors.New("connection reset"), true},
+		{context.Canceled, false},
+		{fail(domainerr.CodeUnimplemented)(), false},
+		{fail(domainerr.CodeDataLoss)(), false},
+		{domainerr.NewFailedPrecondition().WithSpecificCase(gatewayRejected{}).Build(), true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsFailure(tt.err), "%v", tt.err)
+	}
+}
+
+func TestBreaker_ClientFaultsDontTrip(t *testing.T) {
+	b, _, transitions := newTestBreaker(t)
+	for i := 0; i < 10; i++ {
+		_ = b.Do(fail(domainerr.CodeInvalidArgument))
+	}
+	assert.Equal(t, StateClosed, b.State())
+	assert.Empty(t, *transitions)
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b, _, _ := newTestBreaker(t)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(succeed)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_Lifecycle(t *testing.T) {
+	b, clock, transitions := newTestBreaker(t)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(fail(domainerr.CodeInternalError))
+	assert.Equal(t, StateOpen, b.State())
+
+	clock.Advance(4 * time.Second)
+	called := false
+	err := b.Do(func() error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	var de *domainerr.Error
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, domainerr.CodeUnavailable, de.Status().Code())
+	assert.Equal(t, "circuit breaker user-service is open", de.Status().Message())
+	delay, found := RetryDelayOf(err)
+	assert.True(t, found)
+	assert.Equal(t, 6*time.Second, delay)
+
+	// A failed trial call opens the breaker again.
+	clock.Advance(6 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+	_ = b.Do(fail(domainerr.CodeDeadlineExceeded))
+	assert.Equal(t, StateOpen, b.State())
+
+	// A successful trial call closes it.
+	clock.Advance(10 * time.Second)
+	assert.Nil(t, b.Do(succeed))
+	assert.Equal(t, StateClosed, b.State())
+
+	start := fakeclock.Start
+	assert.Equal(t, []Transition{
+		{"user-service", StateClosed, StateOpen, start},
+		{"user-service", StateOpen, StateHalfOpen, start.Add(10 * time.Second)},
+		{"user-service", StateHalfOpen, StateOpen, start.Add(10 * time.Second)},
+		{"user-service", StateOpen, StateHalfOpen, start.Add(20 * time.Second)},
+		{"user-service", StateHalfOpen, StateClosed, start.Add(20 * time.Second)},
+	}, *transitions)
+}
+
+func TestBreaker_HalfOpenMaxCalls(t *testing.T) {
+	b, clock, _ := newTestBreaker(t, WithHalfOpenMaxCalls(2))
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	clock.Advance(10 * time.Second)
+
+	done1, err := b.Allow()
+	assert.Nil(t, err)
+	done2, err := b.Allow()
+	assert.Nil(t, err)
+	_, err = b.Allow()
+	assert.NotNil(t, err)
+
+	done1(nil)
+	assert.Equal(t, StateHalfOpen, b.State())
+	done2(nil)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_StaleResultsIgnored(t *testing.T) {
+	b, clock, _ := newTestBreaker(t)
+	slow, err := b.Allow()
+	assert.Nil(t, err)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	// The result of a call allowed while closed doesn't close the half-open breaker.
+	slow(nil)
+	assert.Equal(t, StateHalfOpen, b.State())
+}
+
+func TestBreaker_PanicIsFailure(t *testing.T) {
+	b, clock, _ := newTestBreaker(t)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	clock.Advance(10 * time.Second)
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = b.Do(func() error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestBreaker_AbandonedTrialReopens(t *testing.T) {
+	b, clock, _ := newTestBreaker(t)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	clock.Advance(10 * time.Second)
+
+	done, err := b.Allow()
+	assert.Nil(t, err)
+	_, err = b.Allow()
+	assert.NotNil(t, err)
+
+	// The trial call never finishes, so the breaker is opened again after the open timeout.
+	clock.Advance(9 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+	clock.Advance(1 * time.Second)
+	assert.Equal(t, StateOpen, b.State())
+	done(nil)
+	assert.Equal(t, StateOpen, b.State())
+
+	clock.Advance(10 * time.Second)
+	assert.Nil(t, b.Do(succeed))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_CanceledIsNeutral(t *testing.T) {
+	b, clock, _ := newTestBreaker(t)
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	_ = b.Do(func() error { return context.Canceled })
+	_ = b.Do(fail(domainerr.CodeUnavailable))
+	assert.Equal(t, StateOpen, b.State())
+	clock.Advance(10 * time.Second)
+
+	// A canceled trial call releases its slot without closing the breaker.
+	assert.Equal(t, context.Canceled, b.Do(func() error { return context.Canceled }))
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.Nil(t, b.Do(succeed))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_DoneCountsOnce(t *testing.T) {
+	b, _, _ := newTestBreaker(t)
+	done, err := b.Allow()
+	assert.Nil(t, err)
+	unavailable := fail(domainerr.CodeUnavailable)()
+	done(unavailable)
+	done(unavailable)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestNew_IllegalArgs(t *testing.T) {
+	opts := []Opt{
+		WithFailureThreshold(0),
+		WithOpenTimeout(0),
+		WithHalfOpenMaxCalls(0),
+		WithFailurePredicate(nil),
+		WithClock(nil),
+	}
+	for _, opt := range opts {
+		b, err := New("user-service", opt)
+		assert.Nil(t, b)
+		assert.NotNil(t, err)
+	}
+}
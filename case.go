@@ -1,5 +1,9 @@
 package domainerr
 
+import (
+	"github.com/pkg/errors"
+)
+
 // Case represents a specific error condition. For example: purchase_limit_exceeded, insufficient_inventory.
 type Case interface {
 	// Identifier returns a string that uniquely identifies this error case. It can be
@@ -10,3 +14,66 @@ type Case interface {
 	// StatusCode returns the operation status Code to which this error case is mapped.
 	StatusCode() Code
 }
+
+// HierarchicalCase is a Case which is a more specific case of its parent, so that the cases form
+// a tree. For example, payment.declined.insufficient_funds is a child of payment.declined, and
+// callers can handle the whole family with CaseIsA(err, paymentDeclined).
+type HierarchicalCase interface {
+	Case
+
+	// Parent returns the parent case, or nil if this case is a root.
+	Parent() Case
+}
+
+// ParentOf returns the parent of c if c is a HierarchicalCase, or nil otherwise.
+func ParentOf(c Case) Case {
+	if hc, ok := c.(HierarchicalCase); ok {
+		if p := hc.Parent(); NotNil(p) {
+			return p
+		}
+	}
+	return nil
+}
+
+// Ancestors returns the ancestors of c, from its parent to the root. A case appearing twice in the
+// chain ends it, so a malformed cycle doesn't loop forever.
+func Ancestors(c Case) []Case {
+	if IsNil(c) {
+		return nil
+	}
+	var ancestors []Case
+	seen := map[string]bool{c.Identifier(): true}
+	for p := ParentOf(c); p != nil && !seen[p.Identifier()]; p = ParentOf(p) {
+		seen[p.Identifier()] = true
+		ancestors = append(ancestors, p)
+	}
+	return ancestors
+}
+
+// CaseIs tells if c is target or a descendant of it. Cases are compared by their identifiers, so
+// cases restored from the wire match the original ones.
+func CaseIs(c Case, target Case) bool {
+	if IsNil(c) || IsNil(target) {
+		return false
+	}
+	if c.Identifier() == target.Identifier() {
+		return true
+	}
+	for _, a := range Ancestors(c) {
+		if a.Identifier() == target.Identifier() {
+			return true
+		}
+	}
+	return false
+}
+
+// CaseIsA tells if any *Error in the cause chain of err has a case which is target or a
+// descendant of it.
+func CaseIsA(err error, target Case) bool {
+	for e := err; NotNil(e); e = errors.UnwrapOnce(e) {
+		if de, ok := e.(*Error); ok && CaseIs(de.status.specificCase, target) {
+			return true
+		}
+	}
+	return false
+}
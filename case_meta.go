@@ -0,0 +1,94 @@
+package domainerr
+
+import (
+	"strings"
+)
+
+// CaseMeta is the optional metadata of a case: its lifecycle, and the fault and the severity
+// overriding the ones of its status code. Case implementations keep it in an unexported field, and
+// set it with its setters, which validate the values, so that the options of different case
+// packages behave the same. The zero value is a public case without overrides.
+type CaseMeta struct {
+	lifecycle Lifecycle
+	fault     Fault
+	severity  Severity
+}
+
+// SetSince sets the version in which the case was introduced, e.g., "v1.2.0".
+func (m *CaseMeta) SetSince(version string) error {
+	version = strings.TrimSpace(version)
+	err := CheckArgument(version != "", "version is blank")
+	if err != nil {
+		return err
+	}
+	m.lifecycle.Since = version
+	return nil
+}
+
+// SetDeprecated marks the case with the given identifier as deprecated. The replacement is the case
+// to use instead, which may be nil if there is none. Building an error with a deprecated case
+// calls the DeprecationHook once.
+func (m *CaseMeta) SetDeprecated(identifier string, replacement Case) error {
+	err := CheckArgument(IsNil(replacement) || replacement.Identifier() != identifier,
+		"case %s is replaced by itself", identifier)
+	if err != nil {
+		return err
+	}
+	m.lifecycle.Deprecated = true
+	if NotNil(replacement) {
+		m.lifecycle.Replacement = replacement
+	}
+	return nil
+}
+
+// SetVisibility sets the visibility of the case, which is VisibilityPublic by default.
+func (m *CaseMeta) SetVisibility(v Visibility) error {
+	err := CheckArgument(v == VisibilityPublic || v == VisibilityInternal, "unknown visibility %q", v)
+	if err != nil {
+		return err
+	}
+	m.lifecycle.Visibility = v
+	return nil
+}
+
+// SetFault overrides the fault of the status code of the case, e.g., DependencyFault for a
+// FailedPrecondition case caused by a rejection of a payment gateway.
+func (m *CaseMeta) SetFault(fault Fault) error {
+	err := CheckArgument(fault == ClientFault || fault == ServerFault || fault == DependencyFault,
+		"illegal fault %q", fault)
+	if err != nil {
+		return err
+	}
+	m.fault = fault
+	return nil
+}
+
+// SetSeverity overrides the severity of the status code of the case.
+func (m *CaseMeta) SetSeverity(sev Severity) error {
+	err := CheckArgument(sev >= SeverityInfo && sev <= SeverityCritical, "illegal severity %d", sev)
+	if err != nil {
+		return err
+	}
+	m.severity = sev
+	return nil
+}
+
+// Lifecycle returns the lifecycle metadata of the case. The Visibility is never empty.
+func (m *CaseMeta) Lifecycle() Lifecycle {
+	l := m.lifecycle
+	if l.Visibility == "" {
+		l.Visibility = VisibilityPublic
+	}
+	return l
+}
+
+// Fault returns the fault overriding the one of the status code, or "" if it isn't overridden.
+func (m *CaseMeta) Fault() Fault {
+	return m.fault
+}
+
+// Severity returns the severity overriding the one of the status code, or 0 if it isn't
+// overridden.
+func (m *CaseMeta) Severity() Severity {
+	return m.severity
+}
@@ -0,0 +1,37 @@
+package domainerr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseMeta(t *testing.T) {
+	var m CaseMeta
+	assert.Equal(t, Lifecycle{Visibility: VisibilityPublic}, m.Lifecycle())
+	assert.Equal(t, Fault(""), m.Fault())
+	assert.Equal(t, Severity(0), m.Severity())
+
+	replacement := &lifecycleCase4Test{identifier: "order.insufficient_inventory"}
+	assert.Nil(t, m.SetSince(" v1.2.0 "))
+	assert.Nil(t, m.SetDeprecated("order.out_of_stock", replacement))
+	assert.Nil(t, m.SetVisibility(VisibilityInternal))
+	assert.Nil(t, m.SetFault(DependencyFault))
+	assert.Nil(t, m.SetSeverity(SeverityWarning))
+	assert.Equal(t, Lifecycle{
+		Since: "v1.2.0", Deprecated: true, Replacement: replacement, Visibility: VisibilityInternal,
+	}, m.Lifecycle())
+	assert.Equal(t, DependencyFault, m.Fault())
+	assert.Equal(t, SeverityWarning, m.Severity())
+}
+
+func TestCaseMeta_IllegalArgs(t *testing.T) {
+	var m CaseMeta
+	assert.EqualError(t, m.SetSince(" "), "illegal argument: version is blank")
+	assert.EqualError(t, m.SetDeprecated("order.out_of_stock", &lifecycleCase4Test{identifier: "order.out_of_stock"}),
+		"illegal argument: case order.out_of_stock is replaced by itself")
+	assert.EqualError(t, m.SetVisibility("secret"), `illegal argument: unknown visibility "secret"`)
+	assert.EqualError(t, m.SetFault(NoFault), `illegal argument: illegal fault "none"`)
+	assert.EqualError(t, m.SetSeverity(0), "illegal argument: illegal severity 0")
+	assert.Equal(t, CaseMeta{}, m)
+}
@@ -0,0 +1,99 @@
+package domainerr
+
+import (
+	"sync"
+)
+
+// CaseRegistry looks up cases by their identifiers, e.g., to restore the cases of errors decoded
+// from the wire.
+type CaseRegistry interface {
+	// Lookup returns the case with the given identifier and true if it is registered. Otherwise,
+	// it returns (nil, false).
+	Lookup(identifier string) (Case, bool)
+}
+
+// MapCaseRegistry is a CaseRegistry backed by a map. It is safe for concurrent use.
+type MapCaseRegistry struct {
+	mu    sync.RWMutex
+	cases map[string]Case
+}
+
+func NewMapCaseRegistry(cases ...Case) *MapCaseRegistry {
+	r := &MapCaseRegistry{cases: make(map[string]Case, len(cases))}
+	r.Register(cases...)
+	return r
+}
+
+// Register registers the given cases. A case replaces the registered one with the same identifier.
+func (r *MapCaseRegistry) Register(cases ...Case) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range cases {
+		r.cases[c.Identifier()] = c
+	}
+}
+
+func (r *MapCaseRegistry) Lookup(identifier string) (Case, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, found := r.cases[identifier]
+	return c, found
+}
+
+// RestoreCase looks up the case with the given identifier in reg. If reg is nil or the case isn't
+// registered, it returns a case with the given identifier and status code.
+func RestoreCase(reg CaseRegistry, identifier string, statusCode Code) Case {
+	if reg != nil {
+		if c, found := reg.Lookup(identifier); found {
+			return c
+		}
+	}
+	return &unregisteredCase{identifier: identifier, statusCode: statusCode}
+}
+
+// RestoreCaseHierarchy is like RestoreCase, but if the case isn't registered, the returned case
+// has the ancestors with the given identifiers, from its parent to the root, so that CaseIsA
+// works with cases decoded from the wire. The ancestors are restored from reg as well.
+func RestoreCaseHierarchy(reg CaseRegistry, identifier string, statusCode Code, ancestorIDs []string) Case {
+	if reg != nil {
+		if c, found := reg.Lookup(identifier); found {
+			return c
+		}
+	}
+	var parent Case
+	if len(ancestorIDs) > 0 {
+		parent = RestoreCaseHierarchy(reg, ancestorIDs[0], statusCode, ancestorIDs[1:])
+	}
+	return &unregisteredCase{identifier: identifier, statusCode: statusCode, parent: parent}
+}
+
+// AncestorIDs returns the identifiers of the ancestors of c, from its parent to the root.
+func AncestorIDs(c Case) []string {
+	ancestors := Ancestors(c)
+	if len(ancestors) == 0 {
+		return nil
+	}
+	ids := make([]string, len(ancestors))
+	for i, a := range ancestors {
+		ids[i] = a.Identifier()
+	}
+	return ids
+}
+
+type unregisteredCase struct {
+	identifier string
+	statusCode Code
+	parent     Case
+}
+
+func (c *unregisteredCase) Identifier() string {
+	return c.identifier
+}
+
+func (c *unregisteredCase) StatusCode() Code {
+	return c.statusCode
+}
+
+func (c *unregisteredCase) Parent() Case {
+	return c.parent
+}
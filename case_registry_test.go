@@ -0,0 +1,50 @@
+package domainerr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapCaseRegistry(t *testing.T) {
+	c := &case4Test{moduleCode: 1, caseCode: 1}
+	r := NewMapCaseRegistry(c)
+
+	found, ok := r.Lookup("1_1")
+	assert.True(t, ok)
+	assert.Equal(t, c, found)
+	_, ok = r.Lookup("1_2")
+	assert.False(t, ok)
+
+	c2 := &case4Test{moduleCode: 1, caseCode: 2}
+	r.Register(c2)
+	found, ok = r.Lookup("1_2")
+	assert.True(t, ok)
+	assert.Equal(t, c2, found)
+}
+
+func TestRestoreCase(t *testing.T) {
+	c := &case4Test{moduleCode: 1, caseCode: 1}
+	assert.Equal(t, c, RestoreCase(NewMapCaseRegistry(c), "1_1", CodeFailedPrecondition))
+
+	for _, reg := range []CaseRegistry{nil, NewMapCaseRegistry(c)} {
+		restored := RestoreCase(reg, "1_2", CodeNotFound)
+		assert.Equal(t, "1_2", restored.Identifier())
+		assert.Equal(t, CodeNotFound, restored.StatusCode())
+	}
+}
+
+func TestRestoreCaseHierarchy(t *testing.T) {
+	declined := &case4Test{moduleCode: 1, caseCode: 1}
+	c := RestoreCaseHierarchy(NewMapCaseRegistry(declined), "1_1_1", CodeFailedPrecondition,
+		[]string{"1_1", "1"})
+	assert.Equal(t, "1_1_1", c.Identifier())
+	assert.Equal(t, CodeFailedPrecondition, c.StatusCode())
+	ancestors := Ancestors(c)
+	assert.Len(t, ancestors, 1)
+	assert.Equal(t, declined, ancestors[0])
+
+	c = RestoreCaseHierarchy(nil, "1_1_1", CodeFailedPrecondition, []string{"1_1", "1"})
+	assert.Equal(t, []string{"1_1", "1"}, AncestorIDs(c))
+	assert.Nil(t, ParentOf(RestoreCase(nil, "1", CodeFailedPrecondition)))
+}
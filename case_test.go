@@ -0,0 +1,76 @@
+package domainerr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type treeCase4Test struct {
+	identifier string
+	parent     Case
+}
+
+func (c *treeCase4Test) Identifier() string {
+	return c.identifier
+}
+
+func (c *treeCase4Test) StatusCode() Code {
+	return CodeFailedPrecondition
+}
+
+func (c *treeCase4Test) Parent() Case {
+	return c.parent
+}
+
+var (
+	paymentDeclined   = &treeCase4Test{identifier: "payment.declined"}
+	insufficientFunds = &treeCase4Test{identifier: "payment.declined.insufficient_funds", parent: paymentDeclined}
+	cardExpired       = &treeCase4Test{identifier: "payment.declined.card_expired", parent: paymentDeclined}
+)
+
+func TestAncestors(t *testing.T) {
+	assert.Nil(t, Ancestors(nil))
+	assert.Nil(t, Ancestors(paymentDeclined))
+	assert.Nil(t, Ancestors(&case4Test{moduleCode: 1, caseCode: 1}))
+	assert.Equal(t, []Case{paymentDeclined}, Ancestors(insufficientFunds))
+
+	leaf := &treeCase4Test{identifier: "payment.declined.insufficient_funds.overdraft", parent: insufficientFunds}
+	assert.Equal(t, []Case{insufficientFunds, paymentDeclined}, Ancestors(leaf))
+	assert.Equal(t, []string{"payment.declined.insufficient_funds", "payment.declined"}, AncestorIDs(leaf))
+
+	// a cycle ends the chain
+	a := &treeCase4Test{identifier: "a"}
+	b := &treeCase4Test{identifier: "b", parent: a}
+	a.parent = b
+	assert.Equal(t, []Case{b}, Ancestors(a))
+}
+
+func TestCaseIs(t *testing.T) {
+	assert.True(t, CaseIs(insufficientFunds, insufficientFunds))
+	assert.True(t, CaseIs(insufficientFunds, paymentDeclined))
+	assert.False(t, CaseIs(paymentDeclined, insufficientFunds))
+	assert.False(t, CaseIs(insufficientFunds, cardExpired))
+	assert.False(t, CaseIs(nil, paymentDeclined))
+	assert.False(t, CaseIs(paymentDeclined, nil))
+
+	// compared by identifiers
+	restored := RestoreCaseHierarchy(nil, insufficientFunds.Identifier(), CodeFailedPrecondition,
+		AncestorIDs(insufficientFunds))
+	assert.True(t, CaseIs(restored, paymentDeclined))
+}
+
+func TestCaseIsA(t *testing.T) {
+	err := NewFailedPrecondition().WithSpecificCase(insufficientFunds).Build()
+	assert.True(t, CaseIsA(err, paymentDeclined))
+	assert.True(t, CaseIsA(err, insufficientFunds))
+	assert.False(t, CaseIsA(err, cardExpired))
+	assert.False(t, CaseIsA(nil, paymentDeclined))
+	assert.False(t, CaseIsA(fmt.Errorf("plain"), paymentDeclined))
+
+	// the cases of causes are matched as well
+	wrapped := NewInternalError().WithCause(errors.Wrap(err, "charge failed")).Build()
+	assert.True(t, CaseIsA(wrapped, paymentDeclined))
+}
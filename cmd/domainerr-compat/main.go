@@ -0,0 +1,88 @@
+// Command domainerr-compat compares two case manifests exported by numcase.Catalog, and reports
+// the changes breaking the clients, e.g., removed cases and changed status codes. It's meant to
+// run as a pre-merge check:
+//
+//	domainerr-compat [-format text|json] old.json new.json
+//
+// The exit code is 0 if there is no breaking change, 1 if there is any, and 2 if the manifests
+// can't be read.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ikonglong/domainerr/numcase"
+)
+
+const (
+	exitCompatible = 0
+	exitBreaking   = 1
+	exitError      = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("domainerr-compat", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "text", "report format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: domainerr-compat [-format text|json] old.json new.json")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() != 2 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		return exitError
+	}
+
+	oldM, err := loadManifest(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	newM, err := loadManifest(flags.Arg(1))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	report := numcase.CompareManifests(oldM, newM)
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err = enc.Encode(report); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	} else {
+		for _, c := range report.Changes {
+			fmt.Fprintln(stdout, c.String())
+		}
+	}
+
+	if report.Breaking() {
+		return exitBreaking
+	}
+	return exitCompatible
+}
+
+func loadManifest(path string) (*numcase.Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	m, err := numcase.LoadManifestJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manifest %s: %w", path, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const oldManifest = `{"cases": [
+	{"identifier": "1_2_101", "appCode": 1, "moduleCode": 2, "caseCode": 101, "statusCode": "NotFound", "httpStatus": 404},
+	{"identifier": "1_2_201", "appCode": 1, "moduleCode": 2, "caseCode": 201, "statusCode": "AlreadyExists", "httpStatus": 409}
+]}`
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	oldPath := writeFile(t, "old.json", oldManifest)
+	addedPath := writeFile(t, "added.json", `{"cases": [
+		{"identifier": "1_2_101", "appCode": 1, "moduleCode": 2, "caseCode": 101, "statusCode": "NotFound", "httpStatus": 404},
+		{"identifier": "1_2_201", "appCode": 1, "moduleCode": 2, "caseCode": 201, "statusCode": "AlreadyExists", "httpStatus": 409},
+		{"identifier": "1_2_001", "appCode": 1, "moduleCode": 2, "caseCode": 1, "statusCode": "InvalidArgument", "httpStatus": 400}
+	]}`)
+	removedPath := writeFile(t, "removed.json", `{"cases": [
+		{"identifier": "1_2_101", "appCode": 1, "moduleCode": 2, "caseCode": 101, "statusCode": "NotFound", "httpStatus": 404}
+	]}`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitCompatible, run([]string{oldPath, addedPath}, &stdout, &stderr))
+	assert.Equal(t, "info case_added 1_2_001: InvalidArgument\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitBreaking, run([]string{oldPath, removedPath}, &stdout, &stderr))
+	assert.Equal(t, "BREAKING case_removed 1_2_201: AlreadyExists\n", stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, exitBreaking, run([]string{"-format", "json", oldPath, removedPath}, &stdout, &stderr))
+	var report map[string]any
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.Equal(t, []any{map[string]any{
+		"kind": "case_removed", "identifier": "1_2_201", "old": "AlreadyExists", "breaking": true,
+	}}, report["changes"])
+}
+
+func TestRun_Error(t *testing.T) {
+	oldPath := writeFile(t, "old.json", oldManifest)
+	invalidPath := writeFile(t, "invalid.json", `[`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitError, run([]string{oldPath}, &stdout, &stderr))
+	assert.Equal(t, exitError, run([]string{"-format", "xml", oldPath, oldPath}, &stdout, &stderr))
+	assert.Equal(t, exitError, run([]string{"-unknown", oldPath, oldPath}, &stdout, &stderr))
+	assert.Equal(t, exitError, run([]string{oldPath, filepath.Join(t.TempDir(), "missing.json")}, &stdout, &stderr))
+	assert.Equal(t, exitError, run([]string{oldPath, invalidPath}, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "usage: domainerr-compat")
+}
@@ -223,6 +223,27 @@ var codeToHTTPStatus = map[Code]*HTTPStatus{
 	CodeAuthorizationExpired: HTTPStatusUnauthorized,
 }
 
+// CodeByName returns the well-defined code with the given name, e.g., CodeUnavailable for
+// "ServiceUnavailable".
+func CodeByName(name string) (Code, bool) {
+	for _, c := range CodeList {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return Code{}, false
+}
+
+// serverFaultCodes are the codes meaning that the operation failed due to a fault of the server
+// or its dependencies rather than the client.
+var serverFaultCodes = map[Code]bool{
+	CodeInternalError:    true,
+	CodeUnknown:          true,
+	CodeDataLoss:         true,
+	CodeUnavailable:      true,
+	CodeDeadlineExceeded: true,
+}
+
 // Code represents a status code of an operation.
 type Code struct {
 	name  string
@@ -250,6 +271,33 @@ func (c *Code) ToHTTPStatus() *HTTPStatus {
 	return codeToHTTPStatus[*c]
 }
 
+// Fault returns who is to blame when an operation fails with this code. It's consistent with the
+// HTTP status: the codes mapped to 4xx are client faults, and the ones mapped to 5xx are server or
+// dependency faults. Codes that are not well-defined are server faults.
+func (c *Code) Fault() Fault {
+	if f, found := codeToFault[*c]; found {
+		return f
+	}
+	return ServerFault
+}
+
+// Severity returns the default severity of a failure with this code. Codes that are not
+// well-defined are SeverityError.
+func (c *Code) Severity() Severity {
+	if sev, found := codeToSeverity[*c]; found {
+		return sev
+	}
+	return SeverityError
+}
+
+// IsServerFault tells if this code means a fault of the server or its dependencies, i.e., it is
+// one of InternalError, Unknown, DataLoss, Unavailable and DeadlineExceeded. Unlike Fault, it
+// doesn't include Unimplemented, which is a permanent fault rather than a failure worth alerting
+// on.
+func (c *Code) IsServerFault() bool {
+	return serverFaultCodes[*c]
+}
+
 func (c *Code) String() string {
 	return fmt.Sprintf("%s(%d)", c.name, c.value)
 }
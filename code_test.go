@@ -149,3 +149,26 @@ func TestStatusCode_AuthorizationExpired(t *testing.T) {
 	assert.Equal(t, "AuthorizationExpired(30)", codeAuthorizationExpired.String())
 	assert.Equal(t, HTTPStatusUnauthorized, codeAuthorizationExpired.ToHTTPStatus())
 }
+
+func TestCode_IsServerFault(t *testing.T) {
+	serverFaults := []Code{CodeInternalError, CodeUnknown, CodeDataLoss, CodeUnavailable, CodeDeadlineExceeded}
+	for _, code := range CodeList {
+		isServerFault := false
+		for _, c := range serverFaults {
+			if code == c {
+				isServerFault = true
+			}
+		}
+		assert.Equal(t, isServerFault, code.IsServerFault(), code.String())
+	}
+}
+
+func TestCodeByName(t *testing.T) {
+	for _, code := range CodeList {
+		c, found := CodeByName(code.Name())
+		assert.True(t, found)
+		assert.Equal(t, code, c)
+	}
+	_, found := CodeByName("Unavailable")
+	assert.False(t, found)
+}
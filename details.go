@@ -0,0 +1,90 @@
+package domainerr
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Multiple details of a status are held as an []any, which is how WithTypedDetail adds a detail
+// to a status already having one, and how the codecs restore statuses with multiple details.
+
+// DetailList returns the details of this status as a list, i.e., the elements if the details are
+// an []any, the details itself if it isn't nil, or an empty list otherwise.
+func (s *Status) DetailList() []any {
+	switch d := s.details.(type) {
+	case nil:
+		return nil
+	case []any:
+		return append([]any(nil), d...)
+	default:
+		return []any{d}
+	}
+}
+
+// WithTypedDetail returns a derived instance of the given status with v added to its details.
+// The details become an []any if the status already has any.
+func WithTypedDetail[T any](s *Status, v T) *Status {
+	switch d := s.details.(type) {
+	case nil:
+		return s.WithDetails(v)
+	case []any:
+		return s.WithDetails(append(append(make([]any, 0, len(d)+1), d...), v))
+	default:
+		return s.WithDetails([]any{d, v})
+	}
+}
+
+// DetailOf returns the first detail of the given status of type T. The details as a whole are
+// checked first, so that T can be the type of details set by WithDetails, e.g., an []any.
+func DetailOf[T any](s *Status) (T, bool) {
+	if v, ok := s.details.(T); ok && s.details != nil {
+		return v, true
+	}
+	for _, d := range s.DetailList() {
+		if v, ok := d.(T); ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// DetailsOf returns all the details of the given status of type T.
+func DetailsOf[T any](s *Status) []T {
+	var result []T
+	for _, d := range s.DetailList() {
+		if v, ok := d.(T); ok {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// DetailsAs returns the first detail of type T of the status of the outermost *Error in the cause
+// chain of err. E.g.,
+//
+//	if quota, ok := domainerr.DetailsAs[*errdetails.QuotaFailure](err); ok {
+//		...
+//	}
+func DetailsAs[T any](err error) (T, bool) {
+	for e := err; NotNil(e); e = errors.UnwrapOnce(e) {
+		if de, ok := e.(*Error); ok && NotNil(de) {
+			return DetailOf[T](de.status)
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// FindDetail is like DetailsAs, but searches the statuses of all the *Error in the cause chain of
+// err, from the outermost one.
+func FindDetail[T any](err error) (T, bool) {
+	for e := err; NotNil(e); e = errors.UnwrapOnce(e) {
+		if de, ok := e.(*Error); ok && NotNil(de) {
+			if v, found := DetailOf[T](de.status); found {
+				return v, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,85 @@
+package domainerr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type quotaDetail4Test struct {
+	Limit int
+}
+
+type retryDetail4Test struct {
+	DelaySeconds int
+}
+
+func TestStatus_DetailList(t *testing.T) {
+	assert.Nil(t, StatusNotFound.DetailList())
+	assert.Equal(t, []any{"a"}, StatusNotFound.WithDetails("a").DetailList())
+	assert.Equal(t, []any{"a", 1}, StatusNotFound.WithDetails([]any{"a", 1}).DetailList())
+	assert.Equal(t, []any{[]string{"a"}}, StatusNotFound.WithDetails([]string{"a"}).DetailList())
+}
+
+func TestWithTypedDetail(t *testing.T) {
+	s := WithTypedDetail(StatusResourceExhausted, quotaDetail4Test{Limit: 10})
+	assert.Equal(t, quotaDetail4Test{Limit: 10}, s.Details())
+	assert.Nil(t, StatusResourceExhausted.Details())
+
+	s2 := WithTypedDetail(s, &retryDetail4Test{DelaySeconds: 3})
+	assert.Equal(t, []any{quotaDetail4Test{Limit: 10}, &retryDetail4Test{DelaySeconds: 3}}, s2.Details())
+	s3 := WithTypedDetail(s2, "more")
+	assert.Len(t, s3.DetailList(), 3)
+	assert.Len(t, s2.DetailList(), 2)
+}
+
+func TestDetailOf(t *testing.T) {
+	s := WithTypedDetail(WithTypedDetail(StatusResourceExhausted, quotaDetail4Test{Limit: 10}),
+		&retryDetail4Test{DelaySeconds: 3})
+	quota, ok := DetailOf[quotaDetail4Test](s)
+	assert.True(t, ok)
+	assert.Equal(t, 10, quota.Limit)
+	retry, ok := DetailOf[*retryDetail4Test](s)
+	assert.True(t, ok)
+	assert.Equal(t, 3, retry.DelaySeconds)
+
+	// a wrong type isn't silently a zero value
+	_, ok = DetailOf[*quotaDetail4Test](s)
+	assert.False(t, ok)
+	_, ok = DetailOf[string](StatusNotFound)
+	assert.False(t, ok)
+
+	// the details as a whole
+	all, ok := DetailOf[[]any](s)
+	assert.True(t, ok)
+	assert.Len(t, all, 2)
+
+	s = WithTypedDetail(s, quotaDetail4Test{Limit: 20})
+	assert.Equal(t, []quotaDetail4Test{{Limit: 10}, {Limit: 20}}, DetailsOf[quotaDetail4Test](s))
+	assert.Nil(t, DetailsOf[string](s))
+}
+
+func TestDetailsAs_FindDetail(t *testing.T) {
+	inner := NewWithStatus(WithTypedDetail(StatusResourceExhausted, quotaDetail4Test{Limit: 10})).Build()
+	outer := NewUnavailable().WithDetails(&retryDetail4Test{DelaySeconds: 3}).
+		WithCause(fmt.Errorf("wrapped: %w", inner)).Build()
+	err := fmt.Errorf("call failed: %w", outer)
+
+	retry, ok := DetailsAs[*retryDetail4Test](err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, retry.DelaySeconds)
+	_, ok = DetailsAs[quotaDetail4Test](err)
+	assert.False(t, ok)
+
+	quota, ok := FindDetail[quotaDetail4Test](err)
+	assert.True(t, ok)
+	assert.Equal(t, 10, quota.Limit)
+	_, ok = FindDetail[string](err)
+	assert.False(t, ok)
+
+	_, ok = DetailsAs[quotaDetail4Test](nil)
+	assert.False(t, ok)
+	_, ok = FindDetail[quotaDetail4Test](fmt.Errorf("plain"))
+	assert.False(t, ok)
+}
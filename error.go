@@ -9,6 +9,9 @@ import (
 type Error struct {
 	cause  error
 	status *Status
+	fields []Field
+	// origin is the error this one is copied from by With or WithFields, or nil if it isn't a copy.
+	origin *Error
 	*stack
 }
 
@@ -30,7 +33,8 @@ func NewError(status *Status, opts ...ErrorOpt) *Error {
 	for _, setOpt := range opts {
 		setOpt(e)
 	}
-	e.stack = errors.Callers(1)
+	e.stack = stackPolicyOf(status).capture(1)
+	warnIfDeprecated(status)
 	return e
 }
 
@@ -64,7 +68,8 @@ func (e *Error) ChainMsg() string {
 }
 
 // AugmentMessage is a shortcut of err.Status().AugmentMessage(...), and augments the message of
-// the status of this error with more contextual information of current use case scenario.
+// the status of this error with more contextual information of current use case scenario. Use With
+// for context that should be queryable rather than shown in the message.
 func (e *Error) AugmentMessage(moreCtx string) {
 	e.status.AugmentMessage(moreCtx)
 }
@@ -75,13 +80,27 @@ func (e *Error) AugmentMessagef(moreCtxFmt string, args ...any) {
 }
 
 // Format implements the fmt.Formatter interface.
+//
+// `%+v` prints this error and its causes with full stack traces, and `%#v` prints them with the
+// global StackFormatter. Both end with a line of the fields merged by FieldsOf, if any.
 func (e *Error) Format(s fmt.State, verb rune) {
-	errors.FormatError(e, s, verb)
+	verbose := verb == 'v' && (s.Flag('#') || s.Flag('+'))
+	if verb == 'v' && s.Flag('#') {
+		GlobalStackFormatter().Fprint(s, e)
+	} else {
+		errors.FormatError(e, s, verb)
+	}
+	if fields := FieldsOf(e); verbose && len(fields) > 0 {
+		fmt.Fprintf(s, "\nfields: %s", formatFields(fields))
+	}
 }
 
 type ErrorBuilder struct {
-	status *Status
-	cause  error
+	status      *Status
+	cause       error
+	fields      []Field
+	stackPolicy *StackPolicy
+	callerSkip  int
 }
 
 func (b *ErrorBuilder) WithMessage(msg string) *ErrorBuilder {
@@ -109,11 +128,32 @@ func (b *ErrorBuilder) WithCause(cause error) *ErrorBuilder {
 	return b
 }
 
+// WithStackPolicy overrides the global StackPolicy for the error to be built.
+func (b *ErrorBuilder) WithStackPolicy(p StackPolicy) *ErrorBuilder {
+	b.stackPolicy = &p
+	return b
+}
+
+// WithCallerSkip makes the stack trace of the error to be built start skip frames above the caller
+// of Build, so that a helper building errors on behalf of its callers doesn't appear at the top of
+// it.
+func (b *ErrorBuilder) WithCallerSkip(skip int) *ErrorBuilder {
+	b.callerSkip = skip
+	return b
+}
+
 func (b *ErrorBuilder) Build() *Error {
+	policy := b.stackPolicy
+	if policy == nil {
+		p := stackPolicyOf(b.status)
+		policy = &p
+	}
+	warnIfDeprecated(b.status)
 	return &Error{
 		status: b.status,
 		cause:  b.cause,
-		stack:  errors.Callers(1),
+		fields: append([]Field(nil), b.fields...),
+		stack:  policy.capture(1 + b.callerSkip),
 	}
 }
 
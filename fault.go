@@ -0,0 +1,167 @@
+package domainerr
+
+import (
+	"errors"
+)
+
+// Fault tells who is to blame for a failed operation.
+type Fault string
+
+const (
+	// NoFault is the fault of CodeOK.
+	NoFault = Fault("none")
+	// ClientFault means the client sent a request that can't succeed, e.g., an invalid argument.
+	// It is consistent with the 4xx HTTP status codes.
+	ClientFault = Fault("client")
+	// ServerFault means the server failed to process a valid request, e.g., due to a bug. It is
+	// consistent with the 5xx HTTP status codes.
+	ServerFault = Fault("server")
+	// DependencyFault means a dependency of the server failed or is overloaded, and the server
+	// itself works as intended. It is consistent with the 5xx HTTP status codes as well.
+	DependencyFault = Fault("dependency")
+)
+
+// IsServerSide tells if this fault is ServerFault or DependencyFault.
+func (f Fault) IsServerSide() bool {
+	return f == ServerFault || f == DependencyFault
+}
+
+// Severity is how urgently a failure needs attention. Severities are ordered, so that adapters can
+// filter them with thresholds, e.g., `severity >= domainerr.SeverityError`.
+type Severity int
+
+const (
+	// SeverityInfo is expected in normal operation, e.g., a resource not found.
+	SeverityInfo Severity = iota + 1
+	// SeverityWarning should be looked into if it happens often, e.g., a dependency timed out.
+	SeverityWarning
+	// SeverityError needs to be fixed, e.g., a bug.
+	SeverityError
+	// SeverityCritical needs immediate attention, e.g., data loss.
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unspecified"
+	}
+}
+
+// FaultCase is a Case that overrides the fault of its status code. A case returning "" doesn't
+// override it.
+type FaultCase interface {
+	Case
+
+	// Fault returns the fault of this case, or "" to use the one of the status code.
+	Fault() Fault
+}
+
+// SeverityCase is a Case that overrides the severity of its status code. A case returning 0
+// doesn't override it.
+type SeverityCase interface {
+	Case
+
+	// Severity returns the severity of this case, or 0 to use the one of the status code.
+	Severity() Severity
+}
+
+var codeToFault = map[Code]Fault{
+	CodeOK:                   NoFault,
+	CodeCancelled:            ClientFault,
+	CodeUnknown:              ServerFault,
+	CodeInvalidArgument:      ClientFault,
+	CodeDeadlineExceeded:     DependencyFault,
+	CodeNotFound:             ClientFault,
+	CodeAlreadyExists:        ClientFault,
+	CodePermissionDenied:     ClientFault,
+	CodeUnauthenticated:      ClientFault,
+	CodeResourceExhausted:    ClientFault,
+	CodeFailedPrecondition:   ClientFault,
+	CodeAborted:              ClientFault,
+	CodeOutOfRange:           ClientFault,
+	CodeUnimplemented:        ServerFault,
+	CodeInternalError:        ServerFault,
+	CodeUnavailable:          DependencyFault,
+	CodeDataLoss:             ServerFault,
+	CodeUndefined:            ClientFault,
+	CodeAuthorizationExpired: ClientFault,
+}
+
+var codeToSeverity = map[Code]Severity{
+	CodeOK:                   SeverityInfo,
+	CodeCancelled:            SeverityInfo,
+	CodeUnknown:              SeverityError,
+	CodeInvalidArgument:      SeverityInfo,
+	CodeDeadlineExceeded:     SeverityWarning,
+	CodeNotFound:             SeverityInfo,
+	CodeAlreadyExists:        SeverityInfo,
+	CodePermissionDenied:     SeverityWarning,
+	CodeUnauthenticated:      SeverityInfo,
+	CodeResourceExhausted:    SeverityWarning,
+	CodeFailedPrecondition:   SeverityInfo,
+	CodeAborted:              SeverityInfo,
+	CodeOutOfRange:           SeverityInfo,
+	CodeUnimplemented:        SeverityError,
+	CodeInternalError:        SeverityError,
+	CodeUnavailable:          SeverityWarning,
+	CodeDataLoss:             SeverityCritical,
+	CodeUndefined:            SeverityInfo,
+	CodeAuthorizationExpired: SeverityInfo,
+}
+
+// Fault returns the fault of this status, which is the one of the specific case if it's a
+// FaultCase overriding it, or the one of the status code otherwise.
+func (s *Status) Fault() Fault {
+	if fc, ok := s.specificCase.(FaultCase); ok && NotNil(fc) {
+		if f := fc.Fault(); f != "" {
+			return f
+		}
+	}
+	return s.code.Fault()
+}
+
+// Severity returns the severity of this status, which is the one of the specific case if it's a
+// SeverityCase overriding it, or the one of the status code otherwise.
+func (s *Status) Severity() Severity {
+	if sc, ok := s.specificCase.(SeverityCase); ok && NotNil(sc) {
+		if sev := sc.Severity(); sev != 0 {
+			return sev
+		}
+	}
+	return s.code.Severity()
+}
+
+// FaultOf returns the fault of the status of the given error. An error that isn't a *Error is
+// handled as an Unknown error, and a nil err has NoFault.
+func FaultOf(err error) Fault {
+	if IsNil(err) {
+		return NoFault
+	}
+	return statusOf(err).Fault()
+}
+
+// SeverityOf returns the severity of the status of the given error. An error that isn't a *Error
+// is handled as an Unknown error, and a nil err has SeverityInfo.
+func SeverityOf(err error) Severity {
+	if IsNil(err) {
+		return SeverityInfo
+	}
+	return statusOf(err).Severity()
+}
+
+func statusOf(err error) *Status {
+	var domainErr *Error
+	if errors.As(err, &domainErr) && NotNil(domainErr) {
+		return domainErr.Status()
+	}
+	return StatusUnknown
+}
@@ -0,0 +1,90 @@
+package domainerr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCode_Fault_ConsistentWithHTTPStatus(t *testing.T) {
+	for _, code := range CodeList {
+		fault := code.Fault()
+		httpStatus := code.ToHTTPStatus()
+		switch {
+		case code == CodeOK:
+			assert.Equal(t, NoFault, fault)
+		case httpStatus == nil:
+			assert.Equal(t, ClientFault, fault, code.String())
+		case httpStatus.Code() >= 500:
+			assert.True(t, fault.IsServerSide(), code.String())
+		default:
+			assert.True(t, httpStatus.Code() >= 400, code.String())
+			assert.Equal(t, ClientFault, fault, code.String())
+		}
+		assert.NotEqual(t, "unspecified", code.Severity().String(), code.String())
+	}
+	assert.Equal(t, DependencyFault, CodeUnavailable.Fault())
+	assert.Equal(t, DependencyFault, CodeDeadlineExceeded.Fault())
+	assert.Equal(t, SeverityCritical, CodeDataLoss.Severity())
+
+	notDefined := newCode("NotDefined", 100)
+	assert.Equal(t, ServerFault, notDefined.Fault())
+	assert.Equal(t, SeverityError, notDefined.Severity())
+}
+
+func TestSeverity(t *testing.T) {
+	assert.True(t, SeverityInfo < SeverityWarning)
+	assert.True(t, SeverityWarning < SeverityError)
+	assert.True(t, SeverityError < SeverityCritical)
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "unspecified", Severity(0).String())
+}
+
+type classifiedCase4Test struct {
+	fault    Fault
+	severity Severity
+}
+
+func (c *classifiedCase4Test) Identifier() string {
+	return "payment.gateway_rejected"
+}
+
+func (c *classifiedCase4Test) StatusCode() Code {
+	return CodeFailedPrecondition
+}
+
+func (c *classifiedCase4Test) Fault() Fault {
+	return c.fault
+}
+
+func (c *classifiedCase4Test) Severity() Severity {
+	return c.severity
+}
+
+func TestStatus_Fault_OverriddenByCase(t *testing.T) {
+	s := NewWithCode(CodeFailedPrecondition).WithCase(&classifiedCase4Test{fault: DependencyFault, severity: SeverityError})
+	assert.Equal(t, DependencyFault, s.Fault())
+	assert.Equal(t, SeverityError, s.Severity())
+
+	s = NewWithCode(CodeFailedPrecondition).WithCase(&classifiedCase4Test{})
+	assert.Equal(t, ClientFault, s.Fault())
+	assert.Equal(t, SeverityInfo, s.Severity())
+
+	s = NewWithCode(CodeInternalError)
+	assert.Equal(t, ServerFault, s.Fault())
+	assert.Equal(t, SeverityError, s.Severity())
+}
+
+func TestFaultOf(t *testing.T) {
+	assert.Equal(t, NoFault, FaultOf(nil))
+	assert.Equal(t, SeverityInfo, SeverityOf(nil))
+	assert.Equal(t, ServerFault, FaultOf(fmt.Errorf("plain")))
+	assert.Equal(t, SeverityError, SeverityOf(fmt.Errorf("plain")))
+
+	err := fmt.Errorf("wrapped: %w", NewFailedPrecondition().
+		WithSpecificCase(&classifiedCase4Test{fault: DependencyFault, severity: SeverityCritical}).Build())
+	assert.Equal(t, DependencyFault, FaultOf(err))
+	assert.Equal(t, SeverityCritical, SeverityOf(err))
+	assert.Equal(t, ClientFault, FaultOf(NewNotFound().Build()))
+}
@@ -0,0 +1,146 @@
+package domainerr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Field is a piece of structured context of an error, e.g., the ID of the order being processed.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// With returns a shallow copy of this error with the given field attached, replacing the value of
+// a field with the same key, so that each layer of the call stack can add its context:
+//
+//	return domainerr.NewNotFound().WithSpecificCase(orderNotFound).Build().With("order_id", id)
+//
+// Unlike AugmentMessage, the fields are kept out of the message. They are shown by `%+v` and
+// `%#v`, logged by slog and encoded as debug info. This error is left untouched, so it is safe to
+// call concurrently, e.g., on a sentinel error shared by goroutines. The copy shares the status,
+// the cause and the stack with this error, and matches it by errors.Is:
+//
+//	err := ErrOrderNotFound.With("order_id", id)
+//	errors.Is(err, ErrOrderNotFound) // true
+//
+// A nil error is returned as it is.
+func (e *Error) With(key string, value any) *Error {
+	if e == nil {
+		return nil
+	}
+	c := e.copy()
+	c.fields = setField(c.fields, key, value)
+	return c
+}
+
+// WithFields returns a shallow copy of this error with the given fields attached in the order of
+// their keys. See With.
+func (e *Error) WithFields(fields map[string]any) *Error {
+	if e == nil {
+		return nil
+	}
+	c := e.copy()
+	for _, k := range sortedKeys(fields) {
+		c.fields = setField(c.fields, k, fields[k])
+	}
+	return c
+}
+
+// Is tells if this error and target are copies of the same original error made by With or
+// WithFields, including the original itself, so that errors.Is matches a sentinel error with
+// fields attached.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || e == nil || t == nil {
+		return false
+	}
+	return e.originOrSelf() == t.originOrSelf()
+}
+
+func (e *Error) copy() *Error {
+	c := *e
+	c.fields = e.Fields()
+	c.origin = e.originOrSelf()
+	return &c
+}
+
+func (e *Error) originOrSelf() *Error {
+	if e.origin != nil {
+		return e.origin
+	}
+	return e
+}
+
+// Fields returns the fields attached to this error itself, in the order they were attached. Use
+// FieldsOf to get the fields of the whole chain.
+func (e *Error) Fields() []Field {
+	return append([]Field(nil), e.fields...)
+}
+
+// With attaches the given field to the error to be built. See (*Error).With.
+func (b *ErrorBuilder) With(key string, value any) *ErrorBuilder {
+	b.fields = setField(b.fields, key, value)
+	return b
+}
+
+// WithFields attaches the given fields to the error to be built. See (*Error).WithFields.
+func (b *ErrorBuilder) WithFields(fields map[string]any) *ErrorBuilder {
+	for _, k := range sortedKeys(fields) {
+		b.fields = setField(b.fields, k, fields[k])
+	}
+	return b
+}
+
+// FieldsOf merges the fields of all the *Error in the cause chain of err. The fields of the root
+// cause come first. A field of an outer error overrides the one with the same key of an inner
+// error, keeping the position of the inner one.
+func FieldsOf(err error) []Field {
+	var layers [][]Field
+	for e := err; NotNil(e); e = errors.UnwrapOnce(e) {
+		if de, ok := e.(*Error); ok && len(de.fields) > 0 {
+			layers = append(layers, de.fields)
+		}
+	}
+	var merged []Field
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, f := range layers[i] {
+			merged = setField(merged, f.Key, f.Value)
+		}
+	}
+	return merged
+}
+
+// formatFields formats the given fields as "key1=value1 key2=value2".
+func formatFields(fields []Field) string {
+	var b strings.Builder
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s=%v", f.Key, f.Value)
+	}
+	return b.String()
+}
+
+func setField(fields []Field, key string, value any) []Field {
+	for i := range fields {
+		if fields[i].Key == key {
+			fields[i].Value = value
+			return fields
+		}
+	}
+	return append(fields, Field{Key: key, Value: value})
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
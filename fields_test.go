@@ -0,0 +1,102 @@
+package domainerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_With(t *testing.T) {
+	e := NewNotFound().With("order_id", 42).Build()
+	assert.Equal(t, []Field{{"order_id", 42}}, e.Fields())
+
+	e2 := e.With("user_id", "u1").With("order_id", 43)
+	assert.NotSame(t, e, e2)
+	assert.Equal(t, []Field{{"order_id", 43}, {"user_id", "u1"}}, e2.Fields())
+	assert.Equal(t, []Field{{"order_id", 42}}, e.Fields())
+	assert.Same(t, e.Status(), e2.Status())
+
+	e3 := e2.WithFields(map[string]any{"b": 2, "a": 1})
+	assert.Equal(t, []Field{{"order_id", 43}, {"user_id", "u1"}, {"a", 1}, {"b", 2}}, e3.Fields())
+	assert.Equal(t, []Field{{"order_id", 43}, {"user_id", "u1"}}, e2.Fields())
+
+	// the message is left untouched
+	assert.Equal(t, "", e3.Status().Message())
+}
+
+func TestError_With_MatchesOriginal(t *testing.T) {
+	sentinel := NewNotFound().WithMessage("order not found").Build()
+	e := sentinel.With("order_id", 42).WithFields(map[string]any{"user_id": "u1"})
+	assert.True(t, errors.Is(e, sentinel))
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", e), sentinel))
+	assert.True(t, errors.Is(sentinel, e))
+	assert.True(t, errors.Is(e, sentinel.With("order_id", 43)))
+	assert.False(t, errors.Is(e, NewNotFound().WithMessage("order not found").Build()))
+	assert.False(t, errors.Is(e, fmt.Errorf("order not found")))
+
+	var nilErr *Error
+	assert.Nil(t, nilErr.With("order_id", 42))
+	assert.Nil(t, nilErr.WithFields(map[string]any{"order_id": 42}))
+	assert.False(t, nilErr.Is(sentinel))
+}
+
+func TestError_With_Concurrent(t *testing.T) {
+	sentinel := NewNotFound().With("order_id", 42).Build()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := sentinel.With("order_id", i).WithFields(map[string]any{"worker": i})
+			assert.Equal(t, []Field{{"order_id", i}, {"worker", i}}, e.Fields())
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, []Field{{"order_id", 42}}, sentinel.Fields())
+}
+
+func TestErrorBuilder_With_NotShared(t *testing.T) {
+	b := NewNotFound().WithFields(map[string]any{"order_id": 42})
+	e1 := b.Build()
+	e2 := b.Build()
+	e1.fields[0].Value = 43
+	assert.Equal(t, []Field{{"order_id", 42}}, e2.Fields())
+	assert.Nil(t, NewNotFound().Build().Fields())
+}
+
+func TestFieldsOf(t *testing.T) {
+	assert.Nil(t, FieldsOf(nil))
+	assert.Nil(t, FieldsOf(fmt.Errorf("plain")))
+
+	root := NewNotFound().Build().With("order_id", 42).With("shard", 1)
+	mid := fmt.Errorf("wrapped: %w", root)
+	outer := NewInternalError().WithCause(mid).Build().With("user_id", "u1").With("shard", 2)
+	assert.Equal(t, []Field{{"order_id", 42}, {"shard", 2}, {"user_id", "u1"}}, FieldsOf(outer))
+	assert.Equal(t, []Field{{"order_id", 42}, {"shard", 1}}, FieldsOf(mid))
+}
+
+func TestError_Format_Fields(t *testing.T) {
+	inner := NewNotFound().WithMessage("order not found").Build().With("order_id", 42)
+	e := NewInternalError().WithCause(inner).Build().With("user_id", "u1")
+
+	assert.Equal(t, e.Error(), fmt.Sprintf("%v", e))
+	assert.Equal(t, e.Error(), fmt.Sprintf("%s", e))
+
+	lines := strings.Split(fmt.Sprintf("%+v", e), "\n")
+	assert.Equal(t, "fields: order_id=42 user_id=u1", lines[len(lines)-1])
+	lines = strings.Split(fmt.Sprintf("%#v", e), "\n")
+	assert.Equal(t, "fields: order_id=42 user_id=u1", lines[len(lines)-1])
+
+	assert.NotContains(t, fmt.Sprintf("%+v", NewNotFound().Build()), "fields:")
+}
+
+func TestError_AugmentMessage_KeptWithFields(t *testing.T) {
+	e := NewNotFound().WithMessage("order not found").Build().With("order_id", 42)
+	e.AugmentMessage("when paying")
+	assert.Equal(t, "order not found\nwhen paying", e.Status().Message())
+	assert.Equal(t, []Field{{"order_id", 42}}, e.Fields())
+}
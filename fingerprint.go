@@ -0,0 +1,216 @@
+package domainerr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultFingerprintDepth is the number of the innermost stack frames taken into account by
+// Fingerprint.
+const DefaultFingerprintDepth = 5
+
+// Fingerprint returns a stable fingerprint for the kind of the given error, which is suitable for
+// alerting and deduplication. It's the same as FingerprintWithDepth(err, DefaultFingerprintDepth).
+func Fingerprint(err error) string {
+	return FingerprintWithDepth(err, DefaultFingerprintDepth)
+}
+
+// FingerprintWithDepth returns a stable fingerprint for the kind of the given error. It hashes
+//   - the status code and the case identifier of the outermost *Error in the chain,
+//   - the function names of the top depth frames of the innermost stack trace in the chain, and
+//   - the types of all the errors in the chain.
+//
+// Messages, details and line numbers are ignored, so errors of the same kind raised at the same
+// place have the same fingerprint. A negative depth is handled as 0. It returns "" for a nil err.
+func FingerprintWithDepth(err error, depth int) string {
+	if IsNil(err) {
+		return ""
+	}
+
+	var sb strings.Builder
+	var domainErr *Error
+	var innermostStack errors.StackTrace
+	for e := err; NotNil(e); e = errors.UnwrapOnce(e) {
+		if de, ok := e.(*Error); ok && domainErr == nil {
+			domainErr = de
+		}
+		if stp, ok := e.(errors.StackTraceProvider); ok {
+			if st := stp.StackTrace(); len(st) > 0 {
+				innermostStack = st
+			}
+		}
+		fmt.Fprintf(&sb, "type:%T\n", e)
+	}
+
+	if domainErr != nil {
+		fmt.Fprintf(&sb, "code:%d\n", domainErr.status.code.value)
+		if c := domainErr.status.specificCase; NotNil(c) {
+			fmt.Fprintf(&sb, "case:%s\n", c.Identifier())
+		}
+	}
+
+	if depth < 0 {
+		depth = 0
+	} else if depth > len(innermostStack) {
+		depth = len(innermostStack)
+	}
+	for _, frame := range innermostStack[:depth] {
+		name := "unknown"
+		if fn := runtime.FuncForPC(uintptr(frame) - 1); fn != nil {
+			name = fn.Name()
+		}
+		fmt.Fprintf(&sb, "func:%s\n", name)
+	}
+
+	sum := sha256.Sum256([]byte(sb.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ErrorGroup is a group of errors with the same fingerprint.
+type ErrorGroup struct {
+	Fingerprint string
+	// Count is the number of occurrences within the sliding window.
+	Count int
+	// Sample is the latest error of this group.
+	Sample   error
+	LastSeen time.Time
+}
+
+// numWindowBuckets is the number of buckets a Grouper's sliding window is divided into.
+const numWindowBuckets = 60
+
+// Grouper counts occurrences of errors per fingerprint over a sliding window. It is safe for
+// concurrent use.
+type Grouper struct {
+	mu          sync.Mutex
+	bucketWidth time.Duration
+	now         func() time.Time
+	groups      map[string]*errorGroup
+	lastSweep   time.Time
+}
+
+type errorGroup struct {
+	sample   error
+	lastSeen time.Time
+	counts   [numWindowBuckets]int
+	epochs   [numWindowBuckets]int64
+}
+
+type GrouperOpt func(g *Grouper)
+
+// WithGrouperClock sets the clock used by a Grouper, which is time.Now by default.
+func WithGrouperClock(now func() time.Time) GrouperOpt {
+	return func(g *Grouper) {
+		g.now = now
+	}
+}
+
+// NewGrouper creates a Grouper counting occurrences within the given window. The window is divided
+// into 60 buckets, so the counts expire with a granularity of window/60.
+func NewGrouper(window time.Duration, opts ...GrouperOpt) (*Grouper, error) {
+	err := CheckArgument(window >= numWindowBuckets, "window < %d", numWindowBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Grouper{
+		bucketWidth: window / numWindowBuckets,
+		now:         time.Now,
+		groups:      make(map[string]*errorGroup),
+	}
+	for _, setOpt := range opts {
+		setOpt(g)
+	}
+	g.lastSweep = g.now()
+	return g, nil
+}
+
+// Add records an occurrence of the given error and returns its fingerprint. A nil err is ignored.
+func (g *Grouper) Add(err error) string {
+	fp := Fingerprint(err)
+	if fp == "" {
+		return ""
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.now()
+	g.sweep(now)
+	grp, found := g.groups[fp]
+	if !found {
+		grp = &errorGroup{}
+		g.groups[fp] = grp
+	}
+	epoch := now.UnixNano() / int64(g.bucketWidth)
+	i := epoch % numWindowBuckets
+	if grp.epochs[i] != epoch {
+		grp.epochs[i] = epoch
+		grp.counts[i] = 0
+	}
+	grp.counts[i]++
+	grp.sample = err
+	grp.lastSeen = now
+	return fp
+}
+
+// sweep evicts the groups without occurrences within the window once per window, so that a
+// Grouper whose Top isn't called doesn't grow without bound. It must be called with the lock held.
+func (g *Grouper) sweep(now time.Time) {
+	if now.Sub(g.lastSweep) < g.bucketWidth*numWindowBuckets {
+		return
+	}
+	currentEpoch := now.UnixNano() / int64(g.bucketWidth)
+	for fp, grp := range g.groups {
+		if grp.lastSeen.UnixNano()/int64(g.bucketWidth) <= currentEpoch-numWindowBuckets {
+			delete(g.groups, fp)
+		}
+	}
+	g.lastSweep = now
+}
+
+// Top returns at most n groups with the most occurrences within the sliding window, in descending
+// order of the count. A negative n returns all the groups. Groups without occurrences within the
+// window are evicted.
+func (g *Grouper) Top(n int) []ErrorGroup {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	currentEpoch := g.now().UnixNano() / int64(g.bucketWidth)
+	list := make([]ErrorGroup, 0, len(g.groups))
+	for fp, grp := range g.groups {
+		count := 0
+		for i, epoch := range grp.epochs {
+			if epoch > currentEpoch-numWindowBuckets {
+				count += grp.counts[i]
+			}
+		}
+		if count == 0 {
+			delete(g.groups, fp)
+			continue
+		}
+		list = append(list, ErrorGroup{
+			Fingerprint: fp,
+			Count:       count,
+			Sample:      grp.sample,
+			LastSeen:    grp.lastSeen,
+		})
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Fingerprint < list[j].Fingerprint
+	})
+	if n >= 0 && n < len(list) {
+		list = list[:n]
+	}
+	return list
+}
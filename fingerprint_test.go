@@ -0,0 +1,115 @@
+package domainerr
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newNotFound(msg string) error {
+	return NewNotFound().WithMessage(msg).Build()
+}
+
+func TestFingerprint(t *testing.T) {
+	assert.Equal(t, "", Fingerprint(nil))
+	var nilErr *Error
+	assert.Equal(t, "", Fingerprint(nilErr))
+
+	// ignores messages and details
+	var fps []string
+	for i := 0; i < 2; i++ {
+		fps = append(fps, Fingerprint(newNotFound(fmt.Sprintf("user %d not found", i))))
+	}
+	assert.Len(t, fps[0], 32)
+	assert.Equal(t, fps[0], fps[1])
+	e := NewNotFound().WithMessage("a").WithDetails(map[string]any{"k": "v"}).Build()
+	e2 := NewNotFound().WithMessage("b").Build()
+	assert.Equal(t, Fingerprint(e), Fingerprint(e2))
+	assert.NotEqual(t, Fingerprint(e), fps[0], "errors built in different functions")
+
+	// takes code and case into account
+	assert.NotEqual(t, Fingerprint(e), Fingerprint(NewAlreadyExists().Build()))
+	c1 := NewFailedPrecondition().WithSpecificCase(&case4Test{moduleCode: 1, caseCode: 1})
+	c2 := NewFailedPrecondition().WithSpecificCase(&case4Test{moduleCode: 1, caseCode: 2})
+	assert.NotEqual(t, Fingerprint(c1.Build()), Fingerprint(c2.Build()))
+
+	// takes types of the cause chain into account
+	withCause := func(cause error) error {
+		return NewInternalError().WithCause(cause).Build()
+	}
+	assert.Equal(t, Fingerprint(withCause(fmt.Errorf("a"))), Fingerprint(withCause(fmt.Errorf("b"))))
+	assert.NotEqual(t, Fingerprint(withCause(fmt.Errorf("a"))), Fingerprint(withCause(nilErr)))
+}
+
+func TestFingerprintWithDepth(t *testing.T) {
+	app := application{s: service{repo: repository{db: database{}}}}
+	err := app.exec("test")
+	// the innermost stack is the one captured in database.insert
+	assert.Equal(t, FingerprintWithDepth(err, 1), FingerprintWithDepth(app.exec("test2"), 1))
+	assert.Equal(t, Fingerprint(err), FingerprintWithDepth(err, DefaultFingerprintDepth))
+	assert.NotEqual(t, FingerprintWithDepth(err, 0), FingerprintWithDepth(err, 1))
+	assert.Equal(t, FingerprintWithDepth(err, 100), FingerprintWithDepth(err, 1000))
+	assert.Equal(t, FingerprintWithDepth(err, 0), FingerprintWithDepth(err, -1))
+}
+
+func TestNewGrouper_IllegalWindow(t *testing.T) {
+	_, err := NewGrouper(time.Nanosecond)
+	assert.EqualError(t, err, "illegal argument: window < 60")
+}
+
+func TestGrouper(t *testing.T) {
+	now := time.Unix(1000, 0)
+	g, err := NewGrouper(time.Minute, WithGrouperClock(func() time.Time { return now }))
+	assert.Nil(t, err)
+	assert.Equal(t, "", g.Add(nil))
+
+	var fpNotFound, fpInternal string
+	for i := 0; i < 3; i++ {
+		fpNotFound = g.Add(newNotFound("not found"))
+	}
+	fpInternal = g.Add(NewInternalError().Build())
+
+	top := g.Top(10)
+	assert.Len(t, top, 2)
+	assert.Equal(t, fpNotFound, top[0].Fingerprint)
+	assert.Equal(t, 3, top[0].Count)
+	assert.Equal(t, now, top[0].LastSeen)
+	assert.Equal(t, fpInternal, top[1].Fingerprint)
+	assert.Equal(t, 1, top[1].Count)
+	assert.Len(t, g.Top(1), 1)
+	assert.Len(t, g.Top(-1), 2)
+
+	// slides the window
+	now = now.Add(30 * time.Second)
+	g.Add(NewInternalError().Build())
+	now = now.Add(31 * time.Second)
+	top = g.Top(10)
+	assert.Len(t, top, 1)
+	assert.Equal(t, fpInternal, top[0].Fingerprint)
+	assert.Equal(t, 1, top[0].Count)
+
+	now = now.Add(time.Minute)
+	assert.Empty(t, g.Top(10))
+	assert.Empty(t, g.groups)
+}
+
+func TestGrouper_AddEvictsExpiredGroups(t *testing.T) {
+	now := time.Unix(1000, 0)
+	g, err := NewGrouper(time.Minute, WithGrouperClock(func() time.Time { return now }))
+	assert.Nil(t, err)
+	g.Add(newNotFound("not found"))
+	g.Add(NewInternalError().Build())
+	assert.Len(t, g.groups, 2)
+
+	now = now.Add(30 * time.Second)
+	fpInternal := g.Add(NewInternalError().Build())
+	assert.Len(t, g.groups, 2)
+
+	// Only Add is called, but the groups not seen within the window are evicted.
+	now = now.Add(31 * time.Second)
+	g.Add(NewInternalError().Build())
+	assert.Len(t, g.groups, 1)
+	assert.Contains(t, g.groups, fpInternal)
+}
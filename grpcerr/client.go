@@ -0,0 +1,171 @@
+package grpcerr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// FromGRPCStatus converts the given gRPC status back to a status, which is the inverse of
+// ToGRPCStatus. The case is looked up in reg by the identifier. If reg is nil or the case isn't
+// registered, a case with the same identifier, status code and ancestors is restored. The details that are
+// proto messages are restored as they are, and the details encoded as JSON are restored as a
+// json.RawMessage. A single detail is restored as the details, and multiple ones are restored as an
+// []any, see domainerr.DetailList.
+func FromGRPCStatus(s *status.Status, reg domainerr.CaseRegistry) *domainerr.Status {
+	if s.Code() == codes.OK {
+		return domainerr.NewWithCode(domainerr.CodeOK)
+	}
+
+	result := domainerr.NewWithCodeValue(int(s.Code()))
+	var caseID string
+	var ancestorIDs []string
+	var jsonDetails json.RawMessage
+	var protoDetails []proto.Message
+	for _, d := range s.Details() {
+		switch v := d.(type) {
+		case *errdetails.ErrorInfo:
+			if v.Domain != ErrorInfoDomain {
+				protoDetails = append(protoDetails, v)
+				break
+			}
+			if codeValue, err := strconv.Atoi(v.Metadata[MetadataCode]); err == nil {
+				result = domainerr.NewWithCodeValue(codeValue)
+			}
+			caseID = v.Metadata[MetadataCaseID]
+			if ids := v.Metadata[MetadataCaseAncestors]; ids != "" {
+				ancestorIDs = strings.Split(ids, ",")
+			}
+			if d, found := v.Metadata[MetadataDetails]; found {
+				jsonDetails = json.RawMessage(d)
+			}
+		case proto.Message:
+			protoDetails = append(protoDetails, v)
+		}
+	}
+
+	result = result.WithMessage(s.Message())
+	if caseID != "" {
+		result = result.WithCase(domainerr.RestoreCaseHierarchy(reg, caseID, result.Code(), ancestorIDs))
+	}
+	for _, d := range protoDetails {
+		result = domainerr.WithTypedDetail[any](result, d)
+	}
+	if jsonDetails != nil {
+		result = domainerr.WithTypedDetail[any](result, jsonDetails)
+	}
+	return result
+}
+
+// RemoteError is the cause of the *domainerr.Error converted from a gRPC status error received by
+// the client interceptors. It records the remote method and the original gRPC status.
+type RemoteError struct {
+	Method string
+	Status *status.Status
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("rpc %s failed: code = %s desc = %s", e.Method, e.Status.Code(), e.Status.Message())
+}
+
+// GRPCStatus returns the original gRPC status, so that status.FromError works with the cause.
+func (e *RemoteError) GRPCStatus() *status.Status {
+	return e.Status
+}
+
+type clientOptions struct {
+	registry domainerr.CaseRegistry
+	retry    *domainerr.RetryExecutor
+}
+
+type ClientOpt func(o *clientOptions)
+
+// WithCaseRegistry sets the CaseRegistry to restore cases from.
+func WithCaseRegistry(reg domainerr.CaseRegistry) ClientOpt {
+	return func(o *clientOptions) {
+		o.registry = reg
+	}
+}
+
+// WithRetryExecutor makes the unary client interceptor retry calls with r, which follows the
+// RetryAdvice of the converted errors.
+func WithRetryExecutor(r *domainerr.RetryExecutor) ClientOpt {
+	return func(o *clientOptions) {
+		o.retry = r
+	}
+}
+
+func newClientOptions(opts []ClientOpt) *clientOptions {
+	o := &clientOptions{}
+	for _, setOpt := range opts {
+		setOpt(o)
+	}
+	return o
+}
+
+// convert converts the given gRPC status error to a *domainerr.Error caused by a RemoteError.
+// Other errors are returned as they are.
+func (o *clientOptions) convert(method string, err error) error {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() == codes.OK {
+		return err
+	}
+	return domainerr.NewWithStatus(FromGRPCStatus(s, o.registry)).
+		WithCause(&RemoteError{Method: method, Status: s}).
+		Build()
+}
+
+// UnaryClientInterceptor returns a grpc.UnaryClientInterceptor which converts the received gRPC
+// status errors to *domainerr.Error with FromGRPCStatus. The cause of the converted errors is a
+// RemoteError recording the remote method.
+func UnaryClientInterceptor(opts ...ClientOpt) grpc.UnaryClientInterceptor {
+	o := newClientOptions(opts)
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		call := func(ctx context.Context) error {
+			return o.convert(method, invoker(ctx, method, req, reply, cc, callOpts...))
+		}
+		if o.retry == nil {
+			return call(ctx)
+		}
+		return o.retry.Do(ctx, call)
+	}
+}
+
+// StreamClientInterceptor returns a grpc.StreamClientInterceptor which converts the gRPC status
+// errors returned by creating streams and by receiving or sending messages, like
+// UnaryClientInterceptor does. Streams are never retried.
+func StreamClientInterceptor(opts ...ClientOpt) grpc.StreamClientInterceptor {
+	o := newClientOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			return nil, o.convert(method, err)
+		}
+		return &clientStream{ClientStream: cs, method: method, opts: o}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	method string
+	opts   *clientOptions
+}
+
+func (s *clientStream) SendMsg(m any) error {
+	return s.opts.convert(s.method, s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	return s.opts.convert(s.method, s.ClientStream.RecvMsg(m))
+}
@@ -0,0 +1,191 @@
+package grpcerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestFromGRPCStatus(t *testing.T) {
+	ok := FromGRPCStatus(status.New(codes.OK, ""), nil)
+	assert.Equal(t, domainerr.StatusOK, ok)
+	assert.NotSame(t, domainerr.StatusOK, ok)
+
+	s := FromGRPCStatus(status.New(codes.NotFound, "no user"), nil)
+	assert.Equal(t, domainerr.CodeNotFound, s.Code())
+	assert.Equal(t, "no user", s.Message())
+	assert.Nil(t, s.SpecificCase())
+	assert.Nil(t, s.Details())
+}
+
+func TestFromGRPCStatus_RoundTrip(t *testing.T) {
+	original := domainerr.StatusFailedPrecondition.WithCaseAndMsg(purchaseLimitExceeded, "limit exceeded").
+		WithDetails(map[string]int{"limit": 10})
+
+	// registered case
+	s := FromGRPCStatus(ToGRPCStatus(original), domainerr.NewMapCaseRegistry(purchaseLimitExceeded))
+	assert.Equal(t, domainerr.CodeFailedPrecondition, s.Code())
+	assert.Equal(t, "limit exceeded", s.Message())
+	assert.Equal(t, purchaseLimitExceeded, s.SpecificCase())
+	assert.Equal(t, json.RawMessage(`{"limit":10}`), s.Details())
+
+	// unregistered case
+	s = FromGRPCStatus(ToGRPCStatus(original), nil)
+	assert.Equal(t, "order.purchase_limit_exceeded", s.SpecificCase().Identifier())
+	assert.Equal(t, domainerr.CodeFailedPrecondition, s.SpecificCase().StatusCode())
+
+	// codes beyond the canonical gRPC codes, and proto details
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)}
+	s = FromGRPCStatus(ToGRPCStatus(domainerr.StatusAuthorizationExpired.WithDetails(retryInfo)), nil)
+	assert.Equal(t, domainerr.CodeAuthorizationExpired, s.Code())
+	assert.True(t, proto.Equal(retryInfo, s.Details().(proto.Message)))
+}
+
+func TestFromGRPCStatus_MultipleDetails(t *testing.T) {
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)}
+	quota := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "user:1"}}}
+	original := domainerr.WithTypedDetail(domainerr.WithTypedDetail(domainerr.WithTypedDetail(
+		domainerr.StatusResourceExhausted, quota), map[string]int{"limit": 10}), retryInfo)
+
+	s := FromGRPCStatus(ToGRPCStatus(original), nil)
+	details := s.DetailList()
+	assert.Len(t, details, 3)
+	restoredQuota, ok := domainerr.DetailOf[*errdetails.QuotaFailure](s)
+	assert.True(t, ok)
+	assert.True(t, proto.Equal(quota, restoredQuota))
+	restoredRetryInfo, ok := domainerr.DetailOf[*errdetails.RetryInfo](s)
+	assert.True(t, ok)
+	assert.True(t, proto.Equal(retryInfo, restoredRetryInfo))
+	jsonDetails, ok := domainerr.DetailOf[json.RawMessage](s)
+	assert.True(t, ok)
+	assert.Equal(t, json.RawMessage(`{"limit":10}`), jsonDetails)
+
+	// multiple JSON details are encoded as an array
+	s = FromGRPCStatus(ToGRPCStatus(domainerr.StatusNotFound.WithDetails([]any{"a", 1})), nil)
+	assert.Equal(t, json.RawMessage(`["a",1]`), s.Details())
+}
+
+func TestFromGRPCStatus_CaseHierarchy(t *testing.T) {
+	declined := domainerr.RestoreCase(nil, "payment.declined", domainerr.CodeFailedPrecondition)
+	insufficientFunds := domainerr.RestoreCaseHierarchy(nil, "payment.declined.insufficient_funds",
+		domainerr.CodeFailedPrecondition, []string{declined.Identifier()})
+
+	grpcStatus := ToGRPCStatus(domainerr.StatusFailedPrecondition.WithCase(insufficientFunds))
+	info := grpcStatus.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "payment.declined", info.Metadata[MetadataCaseAncestors])
+
+	s := FromGRPCStatus(grpcStatus, nil)
+	assert.Equal(t, []string{"payment.declined"}, domainerr.AncestorIDs(s.SpecificCase()))
+	assert.True(t, domainerr.CaseIsA(domainerr.NewError(s), declined))
+
+	info = ToGRPCStatus(domainerr.StatusFailedPrecondition.WithCase(declined)).Details()[0].(*errdetails.ErrorInfo)
+	assert.NotContains(t, info.Metadata, MetadataCaseAncestors)
+}
+
+func TestFromGRPCStatus_ForeignDetails(t *testing.T) {
+	grpcStatus, _ := status.New(codes.InvalidArgument, "bad").WithDetails(
+		&errdetails.ErrorInfo{Reason: "BAD", Domain: "example.com"},
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "name"}}})
+	s := FromGRPCStatus(grpcStatus, nil)
+	assert.Equal(t, domainerr.CodeInvalidArgument, s.Code())
+	assert.Nil(t, s.SpecificCase())
+	assert.Len(t, s.Details(), 2)
+}
+
+// flakyHealthServer returns the errors in errs in turn for Check, and nil when they run out.
+type flakyHealthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	errs  []error
+	calls int
+}
+
+func (s *flakyHealthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	s.calls++
+	if len(s.errs) == 0 {
+		return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return nil, err
+}
+
+func (s *flakyHealthServer) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	return domainerr.NewPermissionDenied().WithMessage("not allowed").Build()
+}
+
+func newClient(t *testing.T, hs grpc_health_v1.HealthServer, opts ...ClientOpt) grpc_health_v1.HealthClient {
+	conn := serve(t, hs, []grpc.ServerOption{
+		grpc.UnaryInterceptor(UnaryServerInterceptor()),
+		grpc.StreamInterceptor(StreamServerInterceptor()),
+	}, grpc.WithUnaryInterceptor(UnaryClientInterceptor(opts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(opts...)))
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	hs := &flakyHealthServer{errs: []error{
+		domainerr.NewFailedPrecondition().WithSpecificCase(purchaseLimitExceeded).WithMessage("limit exceeded").Build(),
+	}}
+	client := newClient(t, hs, WithCaseRegistry(domainerr.NewMapCaseRegistry(purchaseLimitExceeded)))
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	var domainErr *domainerr.Error
+	assert.True(t, errors.As(err, &domainErr))
+	assert.Equal(t, domainerr.CodeFailedPrecondition, domainErr.Status().Code())
+	assert.Equal(t, purchaseLimitExceeded, domainErr.Status().SpecificCase())
+	assert.Equal(t, domainerr.NotRetryUntilStateFixed, domainErr.Status().RetryAdvice())
+
+	var remoteErr *RemoteError
+	assert.True(t, errors.As(err, &remoteErr))
+	assert.Equal(t, "/grpc.health.v1.Health/Check", remoteErr.Method)
+	assert.Equal(t, "rpc /grpc.health.v1.Health/Check failed: code = FailedPrecondition desc = limit exceeded",
+		remoteErr.Error())
+
+	resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+}
+
+func TestUnaryClientInterceptor_Retry(t *testing.T) {
+	retry, _ := domainerr.NewRetryExecutor(3, domainerr.WithBackoff(func(int) time.Duration { return time.Millisecond }))
+
+	hs := &flakyHealthServer{errs: []error{domainerr.NewUnavailable().Build(), domainerr.NewUnavailable().Build()}}
+	client := newClient(t, hs, WithRetryExecutor(retry))
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, hs.calls)
+
+	// not retried
+	hs = &flakyHealthServer{errs: []error{domainerr.NewAborted().Build()}}
+	client = newClient(t, hs, WithRetryExecutor(retry))
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, hs.calls)
+}
+
+func TestStreamClientInterceptor(t *testing.T) {
+	client := newClient(t, &flakyHealthServer{})
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+
+	var domainErr *domainerr.Error
+	assert.True(t, errors.As(err, &domainErr))
+	assert.Equal(t, domainerr.CodePermissionDenied, domainErr.Status().Code())
+	assert.Equal(t, "not allowed", domainErr.Status().Message())
+	var remoteErr *RemoteError
+	assert.True(t, errors.As(err, &remoteErr))
+	assert.Equal(t, "/grpc.health.v1.Health/Watch", remoteErr.Method)
+}
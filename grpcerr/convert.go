@@ -0,0 +1,173 @@
+// Package grpcerr converts between domainerr errors and gRPC statuses, and provides gRPC
+// interceptors doing the conversion.
+package grpcerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ErrorInfoDomain is the domain of the errdetails.ErrorInfo attached to converted gRPC statuses.
+const ErrorInfoDomain = "domainerr"
+
+// The keys of the metadata of the errdetails.ErrorInfo attached to converted gRPC statuses.
+const (
+	MetadataCode    = "code"
+	MetadataCaseID  = "case_id"
+	MetadataDetails = "details"
+	// MetadataCaseAncestors holds the comma-separated identifiers of the ancestors of a
+	// hierarchical case, from its parent to the root. It's absent if the case has no parent.
+	MetadataCaseAncestors = "case_ancestors"
+	// MetadataCaseDeprecation holds the notice returned by domainerr.DeprecationNotice if the case
+	// is deprecated. It's absent otherwise.
+	MetadataCaseDeprecation = "case_deprecation"
+)
+
+var codeToGRPCCode = map[domainerr.Code]codes.Code{
+	domainerr.CodeOK:                   codes.OK,
+	domainerr.CodeCancelled:            codes.Canceled,
+	domainerr.CodeUnknown:              codes.Unknown,
+	domainerr.CodeInvalidArgument:      codes.InvalidArgument,
+	domainerr.CodeDeadlineExceeded:     codes.DeadlineExceeded,
+	domainerr.CodeNotFound:             codes.NotFound,
+	domainerr.CodeAlreadyExists:        codes.AlreadyExists,
+	domainerr.CodePermissionDenied:     codes.PermissionDenied,
+	domainerr.CodeUnauthenticated:      codes.Unauthenticated,
+	domainerr.CodeResourceExhausted:    codes.ResourceExhausted,
+	domainerr.CodeFailedPrecondition:   codes.FailedPrecondition,
+	domainerr.CodeAborted:              codes.Aborted,
+	domainerr.CodeOutOfRange:           codes.OutOfRange,
+	domainerr.CodeUnimplemented:        codes.Unimplemented,
+	domainerr.CodeInternalError:        codes.Internal,
+	domainerr.CodeUnavailable:          codes.Unavailable,
+	domainerr.CodeDataLoss:             codes.DataLoss,
+	domainerr.CodeUndefined:            codes.Unimplemented,
+	domainerr.CodeAuthorizationExpired: codes.Unauthenticated,
+}
+
+// ToGRPCCode returns the gRPC code mapped to the given status code. Codes beyond the canonical gRPC
+// codes are mapped to the closest ones, e.g., AuthorizationExpired to Unauthenticated.
+func ToGRPCCode(code domainerr.Code) codes.Code {
+	if c, found := codeToGRPCCode[code]; found {
+		return c
+	}
+	return codes.Unknown
+}
+
+// Classifier classifies an error that isn't a *domainerr.Error into a status. It returns nil if it
+// can't classify the error.
+type Classifier func(err error) *domainerr.Status
+
+// DefaultClassifier classifies context.Canceled as Cancelled and context.DeadlineExceeded as
+// DeadlineExceeded.
+func DefaultClassifier(err error) *domainerr.Status {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return domainerr.StatusCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return domainerr.StatusDeadlineExceeded
+	}
+	return nil
+}
+
+// ToGRPCStatus converts the given status to a gRPC status. The code, the case identifier and the
+// details are attached as an errdetails.ErrorInfo, in which details that are not proto messages
+// are encoded as JSON, or as a JSON array if there are multiple ones. Details that are proto
+// messages are attached as they are.
+func ToGRPCStatus(s *domainerr.Status) *status.Status {
+	code := s.Code()
+	if code == domainerr.CodeOK {
+		return status.New(codes.OK, s.Message())
+	}
+
+	info := &errdetails.ErrorInfo{
+		Reason:   code.Name(),
+		Domain:   ErrorInfoDomain,
+		Metadata: map[string]string{MetadataCode: strconv.Itoa(code.Value())},
+	}
+	if c := s.SpecificCase(); domainerr.NotNil(c) {
+		info.Reason = c.Identifier()
+		info.Metadata[MetadataCaseID] = c.Identifier()
+		if ids := domainerr.AncestorIDs(c); len(ids) > 0 {
+			info.Metadata[MetadataCaseAncestors] = strings.Join(ids, ",")
+		}
+		if notice := domainerr.DeprecationNotice(c); notice != "" {
+			info.Metadata[MetadataCaseDeprecation] = notice
+		}
+	}
+	details := []proto.Message{info}
+	var jsonDetails []any
+	for _, d := range s.DetailList() {
+		if m, ok := d.(proto.Message); ok {
+			details = append(details, m)
+		} else {
+			jsonDetails = append(jsonDetails, d)
+		}
+	}
+	var encoded any = jsonDetails
+	if len(jsonDetails) == 1 {
+		encoded = jsonDetails[0]
+	}
+	if len(jsonDetails) > 0 {
+		if b, err := json.Marshal(encoded); err == nil {
+			info.Metadata[MetadataDetails] = string(b)
+		}
+	}
+
+	pb := &spb.Status{
+		Code:    int32(ToGRPCCode(code)),
+		Message: s.Message(),
+	}
+	for _, d := range details {
+		if a, err := anypb.New(d); err == nil {
+			pb.Details = append(pb.Details, a)
+		}
+	}
+	return status.FromProto(pb)
+}
+
+// ErrorToGRPCStatus converts the given error to a gRPC status. If err is or wraps a
+// *domainerr.Error, the status of it is converted. If err is a gRPC status error, its status is
+// returned. Otherwise, err is classified by classify, and converted as an Unknown error if it
+// can't be classified. A nil classify means DefaultClassifier.
+func ErrorToGRPCStatus(err error, classify Classifier) *status.Status {
+	if domainerr.IsNil(err) {
+		return nil
+	}
+	if s, isGRPCStatus := toDomainStatus(err, classify); !isGRPCStatus {
+		return ToGRPCStatus(s)
+	}
+	s, _ := status.FromError(err)
+	return s
+}
+
+// toDomainStatus returns the status of the given error if it's a *domainerr.Error, or the
+// classified status otherwise. If err is a gRPC status error instead, it returns (nil, true).
+func toDomainStatus(err error, classify Classifier) (*domainerr.Status, bool) {
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) && domainerr.NotNil(domainErr) {
+		return domainErr.Status(), false
+	}
+	if _, ok := status.FromError(err); ok {
+		return nil, true
+	}
+	if classify == nil {
+		classify = DefaultClassifier
+	}
+	s := classify(err)
+	if s == nil {
+		s = domainerr.StatusUnknown
+	}
+	return s.WithMessage(err.Error()), false
+}
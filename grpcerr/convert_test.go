@@ -0,0 +1,113 @@
+package grpcerr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type testCase string
+
+func (c testCase) Identifier() string {
+	return string(c)
+}
+
+func (c testCase) StatusCode() domainerr.Code {
+	return domainerr.CodeFailedPrecondition
+}
+
+const purchaseLimitExceeded = testCase("order.purchase_limit_exceeded")
+
+func TestToGRPCCode(t *testing.T) {
+	for _, code := range domainerr.CodeList {
+		grpcCode := ToGRPCCode(code)
+		switch code {
+		case domainerr.CodeUndefined:
+			assert.Equal(t, codes.Unimplemented, grpcCode)
+		case domainerr.CodeAuthorizationExpired:
+			assert.Equal(t, codes.Unauthenticated, grpcCode)
+		default:
+			assert.Equal(t, uint32(code.Value()), uint32(grpcCode), code.String())
+		}
+	}
+}
+
+func TestToGRPCStatus_OK(t *testing.T) {
+	s := ToGRPCStatus(domainerr.StatusOK)
+	assert.Equal(t, codes.OK, s.Code())
+	assert.Empty(t, s.Details())
+}
+
+func TestToGRPCStatus(t *testing.T) {
+	s := ToGRPCStatus(domainerr.StatusFailedPrecondition.WithCaseAndMsg(purchaseLimitExceeded, "limit exceeded").
+		WithDetails(map[string]int{"limit": 10}))
+	assert.Equal(t, codes.FailedPrecondition, s.Code())
+	assert.Equal(t, "limit exceeded", s.Message())
+	assert.Len(t, s.Details(), 1)
+	info := s.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "order.purchase_limit_exceeded", info.Reason)
+	assert.Equal(t, ErrorInfoDomain, info.Domain)
+	assert.Equal(t, map[string]string{
+		MetadataCode:    "9",
+		MetadataCaseID:  "order.purchase_limit_exceeded",
+		MetadataDetails: `{"limit":10}`,
+	}, info.Metadata)
+}
+
+func TestToGRPCStatus_ProtoDetails(t *testing.T) {
+	retryInfo := &errdetails.RetryInfo{RetryDelay: durationpb.New(3e9)}
+	s := ToGRPCStatus(domainerr.StatusAuthorizationExpired.WithDetails(retryInfo))
+	assert.Equal(t, codes.Unauthenticated, s.Code())
+	assert.Len(t, s.Details(), 2)
+	info := s.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "AuthorizationExpired", info.Reason)
+	assert.Equal(t, map[string]string{MetadataCode: "30"}, info.Metadata)
+	assert.True(t, proto.Equal(retryInfo, s.Details()[1].(*errdetails.RetryInfo)))
+}
+
+func TestErrorToGRPCStatus(t *testing.T) {
+	assert.Nil(t, ErrorToGRPCStatus(nil, nil))
+
+	s := ErrorToGRPCStatus(fmt.Errorf("wrapped: %w", domainerr.NewNotFound().WithMessage("no user").Build()), nil)
+	assert.Equal(t, codes.NotFound, s.Code())
+	assert.Equal(t, "no user", s.Message())
+
+	grpcErr := status.Error(codes.Aborted, "aborted")
+	assert.Equal(t, grpcErr, ErrorToGRPCStatus(grpcErr, nil).Err())
+
+	s = ErrorToGRPCStatus(fmt.Errorf("call db: %w", context.DeadlineExceeded), nil)
+	assert.Equal(t, codes.DeadlineExceeded, s.Code())
+	assert.Equal(t, "call db: context deadline exceeded", s.Message())
+
+	s = ErrorToGRPCStatus(fmt.Errorf("boom"), nil)
+	assert.Equal(t, codes.Unknown, s.Code())
+
+	s = ErrorToGRPCStatus(fmt.Errorf("boom"), func(err error) *domainerr.Status {
+		return domainerr.StatusUnavailable
+	})
+	assert.Equal(t, codes.Unavailable, s.Code())
+	assert.Equal(t, "boom", s.Message())
+}
+
+type deprecatedCase struct {
+	testCase
+}
+
+func (c deprecatedCase) Lifecycle() domainerr.Lifecycle {
+	return domainerr.Lifecycle{Deprecated: true, Replacement: purchaseLimitExceeded}
+}
+
+func TestToGRPCStatus_DeprecatedCase(t *testing.T) {
+	s := ToGRPCStatus(domainerr.StatusFailedPrecondition.WithCase(deprecatedCase{"order.limit_exceeded"}))
+	info := s.Details()[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "case order.limit_exceeded is deprecated, use order.purchase_limit_exceeded instead",
+		info.Metadata[MetadataCaseDeprecation])
+}
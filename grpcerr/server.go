@@ -0,0 +1,155 @@
+package grpcerr
+
+import (
+	"context"
+	"log"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorHandler handles the original error returned by the handler of the given gRPC method before
+// it is converted, e.g., to log it with its stack trace or to record it as metrics.
+type ErrorHandler func(ctx context.Context, fullMethod string, err error)
+
+// LogServerFaults logs the errors of faults of the server or its dependencies with their stack
+// traces, at the level of their severities, see domainerr.FaultOf and domainerr.SeverityOf. An
+// error that isn't a *domainerr.Error is handled as an Unknown error.
+func LogServerFaults(_ context.Context, fullMethod string, err error) {
+	if !domainerr.FaultOf(err).IsServerSide() {
+		return
+	}
+	log.Printf("[%s] %s failed: %+v\n", levelOf(domainerr.SeverityOf(err)), fullMethod, err)
+}
+
+func levelOf(sev domainerr.Severity) string {
+	switch sev {
+	case domainerr.SeverityInfo:
+		return "Info"
+	case domainerr.SeverityWarning:
+		return "Warn"
+	case domainerr.SeverityCritical:
+		return "Critical"
+	default:
+		return "Error"
+	}
+}
+
+type serverOptions struct {
+	classify    Classifier
+	handleErr   ErrorHandler
+	noRedaction bool
+}
+
+type ServerOpt func(o *serverOptions)
+
+// WithClassifier sets the Classifier for errors that are not *domainerr.Error. The default is
+// DefaultClassifier.
+func WithClassifier(c Classifier) ServerOpt {
+	return func(o *serverOptions) {
+		o.classify = c
+	}
+}
+
+// WithErrorHandler sets the ErrorHandler. The default is LogServerFaults.
+func WithErrorHandler(h ErrorHandler) ServerOpt {
+	return func(o *serverOptions) {
+		o.handleErr = h
+	}
+}
+
+// WithoutRedaction disables redacting the messages and details of server faults, which should be
+// used only for debugging.
+func WithoutRedaction() ServerOpt {
+	return func(o *serverOptions) {
+		o.noRedaction = true
+	}
+}
+
+func newServerOptions(opts []ServerOpt) *serverOptions {
+	o := &serverOptions{
+		classify:  DefaultClassifier,
+		handleErr: LogServerFaults,
+	}
+	for _, setOpt := range opts {
+		setOpt(o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor which converts the errors returned
+// by handlers to gRPC status errors with ErrorToGRPCStatus. The original errors are passed to the
+// ErrorHandler. For server faults, including gRPC status errors returned by downstream services,
+// the message is replaced with the name of the code and the details are dropped, except
+// errdetails.RetryInfo and errdetails.QuotaFailure, so that internals are not leaked.
+func UnaryServerInterceptor(opts ...ServerOpt) grpc.UnaryServerInterceptor {
+	o := newServerOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, o.convert(ctx, info.FullMethod, err)
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor which converts errors like
+// UnaryServerInterceptor does.
+func StreamServerInterceptor(opts ...ServerOpt) grpc.StreamServerInterceptor {
+	o := newServerOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err != nil {
+			return o.convert(ss.Context(), info.FullMethod, err)
+		}
+		return nil
+	}
+}
+
+func (o *serverOptions) convert(ctx context.Context, fullMethod string, err error) error {
+	if o.handleErr != nil {
+		o.handleErr(ctx, fullMethod, err)
+	}
+	s, isGRPCStatus := toDomainStatus(err, o.classify)
+	if isGRPCStatus {
+		if o.noRedaction {
+			return err
+		}
+		// A gRPC status error, e.g., one returned by a downstream service, is redacted too.
+		s = FromGRPCStatus(status.Convert(err), nil)
+		if !s.Fault().IsServerSide() {
+			return err
+		}
+		return ToGRPCStatus(redact(s)).Err()
+	}
+	if !o.noRedaction && s.Fault().IsServerSide() {
+		s = redact(s)
+	}
+	return ToGRPCStatus(s).Err()
+}
+
+// redact replaces the message of the given status with the name of its code, and keeps only the
+// details that are safe to send to clients, see isSafeDetail.
+func redact(s *domainerr.Status) *domainerr.Status {
+	code := s.Code()
+	redacted := s.WithMessage(code.Name()).WithDetails(nil)
+	for _, d := range s.DetailList() {
+		if isSafeDetail(d) {
+			redacted = domainerr.WithTypedDetail[any](redacted, d)
+		}
+	}
+	return redacted
+}
+
+// isSafeDetail tells if the given detail is safe to send to clients even for server faults, i.e.,
+// it tells clients when to retry rather than what went wrong inside the server.
+func isSafeDetail(d any) bool {
+	switch d.(type) {
+	case *errdetails.RetryInfo, *errdetails.QuotaFailure:
+		return true
+	default:
+		return false
+	}
+}
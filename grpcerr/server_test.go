@@ -0,0 +1,200 @@
+package grpcerr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// healthServer returns the error err for both Check and Watch.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	err error
+}
+
+func (s *healthServer) Check(context.Context, *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	return nil, s.err
+}
+
+func (s *healthServer) Watch(*grpc_health_v1.HealthCheckRequest, grpc_health_v1.Health_WatchServer) error {
+	return s.err
+}
+
+// startServer starts a server on a bufconn listener, and returns a client connected to it.
+func startServer(t *testing.T, handlerErr error, opts ...ServerOpt) grpc_health_v1.HealthClient {
+	conn := serve(t, &healthServer{err: handlerErr}, []grpc.ServerOption{
+		grpc.UnaryInterceptor(UnaryServerInterceptor(opts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(opts...)),
+	})
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+// serve starts a server of hs on a bufconn listener, and returns a connection to it.
+func serve(t *testing.T, hs grpc_health_v1.HealthServer, srvOpts []grpc.ServerOption,
+	dialOpts ...grpc.DialOption) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(srvOpts...)
+	grpc_health_v1.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial("bufnet", dialOpts...)
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+// callBoth calls both the unary and the stream methods, and returns their statuses.
+func callBoth(t *testing.T, client grpc_health_v1.HealthClient) []*status.Status {
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	unary := status.Convert(err)
+
+	stream, err := client.Watch(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	return []*status.Status{unary, status.Convert(err)}
+}
+
+func TestServerInterceptors_ClientFault(t *testing.T) {
+	var handled []string
+	handler := WithErrorHandler(func(_ context.Context, fullMethod string, err error) {
+		handled = append(handled, fullMethod)
+	})
+	client := startServer(t, domainerr.NewFailedPrecondition().
+		WithSpecificCase(purchaseLimitExceeded).
+		WithMessage("limit exceeded").
+		WithDetails(map[string]int{"limit": 10}).Build(), handler)
+
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.FailedPrecondition, s.Code())
+		assert.Equal(t, "limit exceeded", s.Message())
+		info := s.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, "order.purchase_limit_exceeded", info.Metadata[MetadataCaseID])
+		assert.Equal(t, `{"limit":10}`, info.Metadata[MetadataDetails])
+	}
+	assert.Equal(t, []string{"/grpc.health.v1.Health/Check", "/grpc.health.v1.Health/Watch"}, handled)
+}
+
+func TestServerInterceptors_ServerFaultRedacted(t *testing.T) {
+	client := startServer(t, domainerr.NewInternalError().
+		WithMessage("db password is wrong").
+		WithDetails(map[string]string{"dsn": "secret"}).Build())
+
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Internal, s.Code())
+		assert.Equal(t, "InternalError", s.Message())
+		info := s.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, map[string]string{MetadataCode: "13"}, info.Metadata)
+	}
+}
+
+func TestServerInterceptors_ServerFaultRedacted_KeepsRetryDetails(t *testing.T) {
+	s := domainerr.StatusUnavailable.WithMessage("circuit breaker db is open")
+	s = domainerr.WithTypedDetail(s, &errdetails.RetryInfo{RetryDelay: durationpb.New(3 * time.Second)})
+	s = domainerr.WithTypedDetail(s, &errdetails.DebugInfo{Detail: "dial tcp 10.0.0.1:5432"})
+	s = domainerr.WithTypedDetail[any](s, map[string]string{"dsn": "secret"})
+	client := startServer(t, domainerr.NewWithStatus(s).Build())
+
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Unavailable, s.Code())
+		assert.Equal(t, "ServiceUnavailable", s.Message())
+		assert.Len(t, s.Details(), 2)
+		info := s.Details()[0].(*errdetails.ErrorInfo)
+		assert.Equal(t, map[string]string{MetadataCode: "14"}, info.Metadata)
+		assert.Equal(t, 3*time.Second, s.Details()[1].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	}
+}
+
+func TestServerInterceptors_GRPCStatusServerFaultRedacted(t *testing.T) {
+	st, _ := status.New(codes.Internal, "db password is wrong").WithDetails(
+		&errdetails.DebugInfo{Detail: "stack"},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{Subject: "user:1"}}})
+	client := startServer(t, st.Err())
+
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Internal, s.Code())
+		assert.Equal(t, "InternalError", s.Message())
+		assert.Len(t, s.Details(), 2)
+		assert.Equal(t, "user:1", s.Details()[1].(*errdetails.QuotaFailure).GetViolations()[0].GetSubject())
+	}
+
+	client = startServer(t, status.Error(codes.Internal, "db password is wrong"), WithoutRedaction())
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, "db password is wrong", s.Message())
+	}
+}
+
+func TestServerInterceptors_WithoutRedaction(t *testing.T) {
+	client := startServer(t, domainerr.NewInternalError().WithMessage("db password is wrong").Build(),
+		WithoutRedaction())
+
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Internal, s.Code())
+		assert.Equal(t, "db password is wrong", s.Message())
+	}
+}
+
+func TestServerInterceptors_ClassifiedPlainError(t *testing.T) {
+	client := startServer(t, fmt.Errorf("connection refused"), WithClassifier(func(err error) *domainerr.Status {
+		return domainerr.StatusUnavailable
+	}), WithoutRedaction())
+
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Unavailable, s.Code())
+		assert.Equal(t, "connection refused", s.Message())
+	}
+
+	client = startServer(t, fmt.Errorf("connection refused"))
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Unknown, s.Code())
+		assert.Equal(t, "UnknownError", s.Message())
+	}
+}
+
+func TestServerInterceptors_GRPCStatusError(t *testing.T) {
+	client := startServer(t, status.Error(codes.Aborted, "aborted by peer"))
+	for _, s := range callBoth(t, client) {
+		assert.Equal(t, codes.Aborted, s.Code())
+		assert.Equal(t, "aborted by peer", s.Message())
+		assert.Empty(t, s.Details())
+	}
+}
+
+func TestLogServerFaults(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	LogServerFaults(context.Background(), "/svc/Get", domainerr.NewNotFound().Build())
+	assert.Empty(t, buf.String())
+
+	LogServerFaults(context.Background(), "/svc/Get", domainerr.NewUnavailable().Build())
+	assert.Contains(t, buf.String(), "[Warn] /svc/Get failed: ")
+
+	buf.Reset()
+	LogServerFaults(context.Background(), "/svc/Get", domainerr.NewDataLoss().Build())
+	assert.Contains(t, buf.String(), "[Critical] /svc/Get failed: ")
+
+	buf.Reset()
+	LogServerFaults(context.Background(), "/svc/Get", fmt.Errorf("boom"))
+	assert.Contains(t, buf.String(), "[Error] /svc/Get failed: boom")
+}
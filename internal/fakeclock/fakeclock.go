@@ -0,0 +1,44 @@
+// Package fakeclock provides a clock controlled by tests, to be passed to the WithClock options of
+// time-based components, e.g., breaker.WithClock(clock.Now).
+package fakeclock
+
+import (
+	"sync"
+	"time"
+)
+
+// Start is the initial time of the clocks created by New, i.e., 2023-11-14T22:13:20Z.
+var Start = time.Unix(1700000000, 0)
+
+// Clock is a clock whose time only changes when it is set or advanced. It is safe for concurrent
+// use.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// New creates a Clock at Start.
+func New() *Clock {
+	return &Clock{now: Start}
+}
+
+// Now returns the current time of the clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the current time of the clock.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
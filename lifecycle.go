@@ -0,0 +1,127 @@
+package domainerr
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Visibility tells who may see a case.
+type Visibility string
+
+const (
+	// VisibilityPublic means the case is part of the public API. It is the default.
+	VisibilityPublic = Visibility("public")
+	// VisibilityInternal means the case is used only inside the system, and should not be
+	// documented for, or relied on by, external clients.
+	VisibilityInternal = Visibility("internal")
+)
+
+// Lifecycle is the optional lifecycle metadata of a case.
+type Lifecycle struct {
+	// Since is the version in which the case was introduced, e.g., "v1.2.0". Empty if unknown.
+	Since string
+	// Deprecated tells if the case is being retired.
+	Deprecated bool
+	// Replacement is the case to use instead of a deprecated case. It may be nil.
+	Replacement Case
+	// Visibility is VisibilityPublic if empty.
+	Visibility Visibility
+}
+
+// IsInternal tells if the visibility is VisibilityInternal.
+func (l Lifecycle) IsInternal() bool {
+	return l.Visibility == VisibilityInternal
+}
+
+// LifecycleCase is a Case with lifecycle metadata.
+type LifecycleCase interface {
+	Case
+
+	// Lifecycle returns the lifecycle metadata of this case.
+	Lifecycle() Lifecycle
+}
+
+// LifecycleOf returns the lifecycle metadata of c if c is a LifecycleCase. Otherwise, it returns a
+// Lifecycle of a public case which isn't deprecated. The returned Visibility is never empty.
+func LifecycleOf(c Case) Lifecycle {
+	var l Lifecycle
+	if lc, ok := c.(LifecycleCase); ok && NotNil(lc) {
+		l = lc.Lifecycle()
+	}
+	if l.Visibility == "" {
+		l.Visibility = VisibilityPublic
+	}
+	return l
+}
+
+// DeprecationNotice returns a notice telling that c is deprecated and what replaces it, e.g.,
+// "case order.out_of_stock is deprecated, use order.insufficient_inventory instead". It returns an
+// empty string if c isn't deprecated.
+func DeprecationNotice(c Case) string {
+	if IsNil(c) {
+		return ""
+	}
+	l := LifecycleOf(c)
+	if !l.Deprecated {
+		return ""
+	}
+	if NotNil(l.Replacement) {
+		return fmt.Sprintf("case %s is deprecated, use %s instead", c.Identifier(), l.Replacement.Identifier())
+	}
+	return fmt.Sprintf("case %s is deprecated", c.Identifier())
+}
+
+// DeprecationHook is called the first time an error is built with a deprecated case.
+type DeprecationHook func(c Case, notice string)
+
+// LogDeprecation logs the notice with the standard logger. It is the initial DeprecationHook.
+func LogDeprecation(_ Case, notice string) {
+	log.Printf("[Warn] %s\n", notice)
+}
+
+var deprecationWarnings = struct {
+	sync.RWMutex
+	hook   DeprecationHook
+	warned sync.Map
+}{hook: LogDeprecation}
+
+// SetDeprecationHook sets the hook called the first time an error is built with a deprecated
+// case. A nil h restores LogDeprecation. To ignore deprecations, set a hook doing nothing.
+func SetDeprecationHook(h DeprecationHook) {
+	if h == nil {
+		h = LogDeprecation
+	}
+	deprecationWarnings.Lock()
+	defer deprecationWarnings.Unlock()
+	deprecationWarnings.hook = h
+}
+
+// ResetDeprecationWarnings forgets the deprecated cases already warned about, so that they are
+// warned about again.
+func ResetDeprecationWarnings() {
+	deprecationWarnings.warned.Range(func(id, _ any) bool {
+		deprecationWarnings.warned.Delete(id)
+		return true
+	})
+}
+
+// warnIfDeprecated calls the DeprecationHook if the specific case of s is deprecated and hasn't
+// been warned about.
+func warnIfDeprecated(s *Status) {
+	if s == nil {
+		return
+	}
+	c := s.specificCase
+	notice := DeprecationNotice(c)
+	if notice == "" {
+		return
+	}
+	if _, warned := deprecationWarnings.warned.LoadOrStore(c.Identifier(), true); warned {
+		return
+	}
+	deprecationWarnings.RLock()
+	hook := deprecationWarnings.hook
+	deprecationWarnings.RUnlock()
+	hook(c, notice)
+}
@@ -0,0 +1,67 @@
+package domainerr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lifecycleCase4Test struct {
+	identifier string
+	lifecycle  Lifecycle
+}
+
+func (c *lifecycleCase4Test) Identifier() string {
+	return c.identifier
+}
+
+func (c *lifecycleCase4Test) StatusCode() Code {
+	return CodeNotFound
+}
+
+func (c *lifecycleCase4Test) Lifecycle() Lifecycle {
+	return c.lifecycle
+}
+
+func TestLifecycleOf(t *testing.T) {
+	assert.Equal(t, Lifecycle{Visibility: VisibilityPublic}, LifecycleOf(&treeCase4Test{identifier: "a"}))
+	assert.Equal(t, Lifecycle{Visibility: VisibilityPublic}, LifecycleOf(nil))
+
+	internal := &lifecycleCase4Test{identifier: "a", lifecycle: Lifecycle{Since: "v1.2.0", Visibility: VisibilityInternal}}
+	assert.Equal(t, internal.lifecycle, LifecycleOf(internal))
+	assert.True(t, LifecycleOf(internal).IsInternal())
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	replacement := &lifecycleCase4Test{identifier: "order.insufficient_inventory"}
+	assert.Equal(t, "", DeprecationNotice(nil))
+	assert.Equal(t, "", DeprecationNotice(replacement))
+	assert.Equal(t, "case order.out_of_stock is deprecated",
+		DeprecationNotice(&lifecycleCase4Test{identifier: "order.out_of_stock", lifecycle: Lifecycle{Deprecated: true}}))
+	assert.Equal(t, "case order.out_of_stock is deprecated, use order.insufficient_inventory instead",
+		DeprecationNotice(&lifecycleCase4Test{
+			identifier: "order.out_of_stock",
+			lifecycle:  Lifecycle{Deprecated: true, Replacement: replacement},
+		}))
+}
+
+func TestDeprecationHook(t *testing.T) {
+	var notices []string
+	SetDeprecationHook(func(_ Case, notice string) {
+		notices = append(notices, notice)
+	})
+	defer SetDeprecationHook(nil)
+	defer ResetDeprecationWarnings()
+
+	deprecated := &lifecycleCase4Test{identifier: "order.out_of_stock", lifecycle: Lifecycle{Deprecated: true}}
+	NewNotFound().WithSpecificCase(&lifecycleCase4Test{identifier: "order.not_deprecated"}).Build()
+	assert.Empty(t, notices)
+
+	NewNotFound().WithSpecificCase(deprecated).Build()
+	NewError(NewWithCode(CodeNotFound).WithCase(deprecated))
+	assert.Equal(t, []string{"case order.out_of_stock is deprecated"}, notices)
+
+	ResetDeprecationWarnings()
+	NewError(NewWithCode(CodeNotFound).WithCase(deprecated))
+	assert.Len(t, notices, 2)
+}
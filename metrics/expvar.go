@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// ExpvarRecorder counts errors in an expvar.Map, keyed by the labels formatted as
+// "code=NotFound,case=order.not_found,http_status=404,class=client_fault".
+type ExpvarRecorder struct {
+	guard  *CaseGuard
+	counts *expvar.Map
+}
+
+// NewExpvarRecorder creates an ExpvarRecorder counting errors in m, which is typically created by
+// expvar.NewMap.
+func NewExpvarRecorder(m *expvar.Map, guard *CaseGuard) (*ExpvarRecorder, error) {
+	err := domainerr.CheckArgument(m != nil, "m is nil")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(guard != nil, "guard is nil")
+	if err != nil {
+		return nil, err
+	}
+	return &ExpvarRecorder{
+		guard:  guard,
+		counts: m,
+	}, nil
+}
+
+func (r *ExpvarRecorder) Record(err error) {
+	if domainerr.IsNil(err) {
+		return
+	}
+	r.counts.Add(r.guard.Labels(err).key(), 1)
+}
+
+func (l Labels) key() string {
+	return fmt.Sprintf("code=%s,case=%s,http_status=%s,class=%s", l.Code, l.Case, l.HTTPStatus, l.Class)
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewExpvarRecorder_IllegalArgument(t *testing.T) {
+	_, err := NewExpvarRecorder(nil, mustNewCaseGuard(t))
+	assert.EqualError(t, err, "illegal argument: m is nil")
+	_, err = NewExpvarRecorder(new(expvar.Map).Init(), nil)
+	assert.EqualError(t, err, "illegal argument: guard is nil")
+}
+
+func TestExpvarRecorder_Record(t *testing.T) {
+	m := new(expvar.Map).Init()
+	r, err := NewExpvarRecorder(m, mustNewCaseGuard(t, userNotFound))
+	assert.Nil(t, err)
+
+	r.Record(nil)
+	r.Record(domainerr.NewNotFound().WithSpecificCase(userNotFound).Build())
+	r.Record(domainerr.NewNotFound().WithSpecificCase(userNotFound).Build())
+	r.Record(domainerr.NewInternalError().Build())
+
+	assert.Equal(t, "2", m.Get("code=NotFound,case=user.not_found,http_status=404,class=client_fault").String())
+	assert.Equal(t, "1", m.Get("code=InternalError,case=,http_status=500,class=server_fault").String())
+	numKeys := 0
+	m.Do(func(expvar.KeyValue) { numKeys++ })
+	assert.Equal(t, 2, numKeys)
+}
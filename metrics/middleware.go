@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/grpc"
+)
+
+// ErrorHandlerFunc is an HTTP handler which returns an error instead of writing it to the response.
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// HTTPMiddleware adapts h to an http.Handler. An error returned by h is recorded with rec, and then
+// written to the response by writeErr. If writeErr is nil, WriteError is used.
+func HTTPMiddleware(rec Recorder, h ErrorHandlerFunc, writeErr func(http.ResponseWriter, *http.Request, error)) http.Handler {
+	if writeErr == nil {
+		writeErr = func(w http.ResponseWriter, _ *http.Request, err error) {
+			WriteError(w, err)
+		}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			rec.Record(err)
+			writeErr(w, r, err)
+		}
+	})
+}
+
+// WriteError replies to the request with the HTTP status mapped to the code of the given error, and
+// the status string as the body. Like the grpcerr interceptors, it doesn't send the message and
+// the details of a fault of the server or its dependencies, whose body is the code name only. An
+// error that isn't a *domainerr.Error is replied with 500.
+func WriteError(w http.ResponseWriter, err error) {
+	status := domainerr.StatusUnknown
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) && domainerr.NotNil(domainErr) {
+		status = domainErr.Status()
+	}
+	code := status.Code()
+	httpStatus := http.StatusInternalServerError
+	if s := code.ToHTTPStatus(); s != nil {
+		httpStatus = s.Code()
+	}
+	body := status.String()
+	if status.Fault().IsServerSide() {
+		body = code.Name()
+	}
+	http.Error(w, body, httpStatus)
+}
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor recording the errors returned by
+// handlers with rec.
+func UnaryServerInterceptor(rec Recorder) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			rec.Record(err)
+		}
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a grpc.StreamServerInterceptor recording the errors returned by
+// handlers with rec.
+func StreamServerInterceptor(rec Recorder) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err != nil {
+			rec.Record(err)
+		}
+		return err
+	}
+}
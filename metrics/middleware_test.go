@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type fakeRecorder struct {
+	errs []error
+}
+
+func (r *fakeRecorder) Record(err error) {
+	r.errs = append(r.errs, err)
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	rec := &fakeRecorder{}
+	notFound := domainerr.NewNotFound().WithMessage("no such user").Build()
+	h := HTTPMiddleware(rec, func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+		return notFound
+	}, nil)
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, rec.errs)
+
+	resp = httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, "NotFound: no such user\n", resp.Body.String())
+	assert.Equal(t, []error{notFound}, rec.errs)
+}
+
+func TestHTTPMiddleware_ServerFaultRedacted(t *testing.T) {
+	h := HTTPMiddleware(&fakeRecorder{}, func(w http.ResponseWriter, r *http.Request) error {
+		return domainerr.NewInternalError().WithMessage("connect to db-7:5432 failed").Build()
+	}, nil)
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "InternalError\n", resp.Body.String())
+	assert.NotContains(t, resp.Body.String(), "db-7")
+}
+
+func TestHTTPMiddleware_CustomWriter(t *testing.T) {
+	h := HTTPMiddleware(&fakeRecorder{}, func(w http.ResponseWriter, r *http.Request) error {
+		return domainerr.NewNotFound().Build()
+	}, func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusTeapot, resp.Code)
+}
+
+func TestWriteError_PlainError(t *testing.T) {
+	resp := httptest.NewRecorder()
+	WriteError(resp, context.Canceled)
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "UnknownError\n", resp.Body.String())
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	rec := &fakeRecorder{}
+	interceptor := UnaryServerInterceptor(rec)
+	invalidArg := domainerr.NewInvalidArgument().Build()
+
+	_, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) { return "resp", nil })
+	assert.Nil(t, err)
+	_, err = interceptor(context.Background(), "req", &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) { return nil, invalidArg })
+	assert.Equal(t, invalidArg, err)
+	assert.Equal(t, []error{invalidArg}, rec.errs)
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	rec := &fakeRecorder{}
+	interceptor := StreamServerInterceptor(rec)
+	aborted := domainerr.NewAborted().Build()
+
+	err := interceptor(nil, nil, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error { return nil })
+	assert.Nil(t, err)
+	err = interceptor(nil, nil, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error { return aborted })
+	assert.Equal(t, aborted, err)
+	assert.Equal(t, []error{aborted}, rec.errs)
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/ikonglong/domainerr"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusRecorder counts errors with the counter vector domainerr_errors_total.
+type PrometheusRecorder struct {
+	guard    *CaseGuard
+	counters *prometheus.CounterVec
+}
+
+// NewPrometheusRecorder creates a PrometheusRecorder and registers its counter vector with reg.
+func NewPrometheusRecorder(reg prometheus.Registerer, guard *CaseGuard) (*PrometheusRecorder, error) {
+	err := domainerr.CheckArgument(reg != nil, "reg is nil")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(guard != nil, "guard is nil")
+	if err != nil {
+		return nil, err
+	}
+
+	counters := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "domainerr_errors_total",
+		Help: "Number of errors by domainerr code, case, HTTP status and fault class.",
+	}, []string{"code", "case", "http_status", "class"})
+	if err = reg.Register(counters); err != nil {
+		return nil, err
+	}
+	return &PrometheusRecorder{
+		guard:    guard,
+		counters: counters,
+	}, nil
+}
+
+func (r *PrometheusRecorder) Record(err error) {
+	if domainerr.IsNil(err) {
+		return
+	}
+	l := r.guard.Labels(err)
+	r.counters.WithLabelValues(l.Code, l.Case, l.HTTPStatus, l.Class).Inc()
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPrometheusRecorder_IllegalArgument(t *testing.T) {
+	_, err := NewPrometheusRecorder(nil, mustNewCaseGuard(t))
+	assert.EqualError(t, err, "illegal argument: reg is nil")
+	_, err = NewPrometheusRecorder(prometheus.NewRegistry(), nil)
+	assert.EqualError(t, err, "illegal argument: guard is nil")
+}
+
+func TestNewPrometheusRecorder_AlreadyRegistered(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	_, err := NewPrometheusRecorder(reg, mustNewCaseGuard(t))
+	assert.Nil(t, err)
+	_, err = NewPrometheusRecorder(reg, mustNewCaseGuard(t))
+	assert.NotNil(t, err)
+}
+
+func TestPrometheusRecorder_Record(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r, err := NewPrometheusRecorder(reg, mustNewCaseGuard(t, userNotFound))
+	assert.Nil(t, err)
+
+	r.Record(nil)
+	r.Record(domainerr.NewNotFound().WithSpecificCase(userNotFound).Build())
+	r.Record(domainerr.NewNotFound().WithSpecificCase(userNotFound).Build())
+	r.Record(domainerr.NewNotFound().WithSpecificCase(orderNotFound).Build())
+	r.Record(domainerr.NewInternalError().Build())
+
+	assert.Equal(t, 3, testutil.CollectAndCount(reg, "domainerr_errors_total"))
+	assert.Equal(t, 2.0, testutil.ToFloat64(r.counters.WithLabelValues("NotFound", "user.not_found", "404", ClassClientFault)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(r.counters.WithLabelValues("NotFound", OtherCase, "404", ClassClientFault)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(r.counters.WithLabelValues("InternalError", "", "500", ClassServerFault)))
+}
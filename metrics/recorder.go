@@ -0,0 +1,107 @@
+// Package metrics counts errors by domainerr code and case, to build error-rate dashboards.
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// The values of the label class.
+const (
+	ClassServerFault     = "server_fault"
+	ClassClientFault     = "client_fault"
+	ClassDependencyFault = "dependency_fault"
+)
+
+// OtherCase is the value of the label case for the case identifiers not registered in a CaseGuard.
+const OtherCase = "other"
+
+// Recorder records errors as metrics.
+type Recorder interface {
+	// Record counts the given error. A nil err is ignored.
+	Record(err error)
+}
+
+// Labels are the labels which an error is counted by.
+type Labels struct {
+	// Code is the name of the status code. An error that isn't a *domainerr.Error is counted as
+	// UnknownError.
+	Code string
+	// Case is the identifier of the specific case, OtherCase if the identifier isn't registered,
+	// or "" if the error has no specific case.
+	Case string
+	// HTTPStatus is the code of the HTTP status mapped to the status code, or "" if not mapped.
+	HTTPStatus string
+	// Class is ClassServerFault, ClassClientFault or ClassDependencyFault, derived from the fault of
+	// the status, see domainerr.FaultOf. Successful operations are not recorded, so there is no
+	// class for domainerr.NoFault.
+	Class string
+}
+
+// CaseGuard bounds the cardinality of the label case by collapsing the case identifiers not
+// registered into OtherCase. It is safe for concurrent use.
+type CaseGuard struct {
+	mu         sync.RWMutex
+	registered map[string]bool
+}
+
+func NewCaseGuard(cases ...domainerr.Case) (*CaseGuard, error) {
+	g := &CaseGuard{registered: make(map[string]bool, len(cases))}
+	if err := g.Register(cases...); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Register registers the given cases, so that their identifiers are used as the label case as is.
+// If any of the cases is nil, none of them is registered.
+func (g *CaseGuard) Register(cases ...domainerr.Case) error {
+	for i, c := range cases {
+		err := domainerr.CheckArgument(domainerr.NotNil(c), "case at index %d is nil", i)
+		if err != nil {
+			return err
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, c := range cases {
+		g.registered[c.Identifier()] = true
+	}
+	return nil
+}
+
+// Labels returns the labels which the given error is counted by.
+func (g *CaseGuard) Labels(err error) Labels {
+	status := domainerr.StatusUnknown
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) && domainerr.NotNil(domainErr) {
+		status = domainErr.Status()
+	}
+
+	code := status.Code()
+	labels := Labels{
+		Code:  code.Name(),
+		Class: ClassClientFault,
+	}
+	switch status.Fault() {
+	case domainerr.ServerFault:
+		labels.Class = ClassServerFault
+	case domainerr.DependencyFault:
+		labels.Class = ClassDependencyFault
+	}
+	if httpStatus := code.ToHTTPStatus(); httpStatus != nil {
+		labels.HTTPStatus = strconv.Itoa(httpStatus.Code())
+	}
+	if c := status.SpecificCase(); domainerr.NotNil(c) {
+		labels.Case = OtherCase
+		g.mu.RLock()
+		if g.registered[c.Identifier()] {
+			labels.Case = c.Identifier()
+		}
+		g.mu.RUnlock()
+	}
+	return labels
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCase string
+
+func (c testCase) Identifier() string {
+	return string(c)
+}
+
+func (c testCase) StatusCode() domainerr.Code {
+	return domainerr.CodeNotFound
+}
+
+const (
+	userNotFound  = testCase("user.not_found")
+	orderNotFound = testCase("order.not_found")
+)
+
+func TestCaseGuard_Labels(t *testing.T) {
+	g := mustNewCaseGuard(t, userNotFound)
+
+	assert.Equal(t, Labels{Code: "NotFound", Case: "user.not_found", HTTPStatus: "404", Class: ClassClientFault},
+		g.Labels(domainerr.NewNotFound().WithSpecificCase(userNotFound).Build()))
+	assert.Equal(t, Labels{Code: "NotFound", Case: OtherCase, HTTPStatus: "404", Class: ClassClientFault},
+		g.Labels(domainerr.NewNotFound().WithSpecificCase(orderNotFound).Build()))
+	assert.Equal(t, Labels{Code: "InternalError", HTTPStatus: "500", Class: ClassServerFault},
+		g.Labels(fmt.Errorf("wrapped: %w", domainerr.NewInternalError().Build())))
+	assert.Equal(t, Labels{Code: "UnknownError", HTTPStatus: "500", Class: ClassServerFault},
+		g.Labels(fmt.Errorf("plain error")))
+	assert.Equal(t, Labels{Code: "OperationNotDefined", Class: ClassClientFault},
+		g.Labels(domainerr.NewUndefined().Build()))
+	assert.Equal(t, Labels{Code: "ServiceUnavailable", HTTPStatus: "503", Class: ClassDependencyFault},
+		g.Labels(domainerr.NewUnavailable().Build()))
+
+	assert.Nil(t, g.Register(orderNotFound))
+	assert.Equal(t, "order.not_found", g.Labels(domainerr.NewNotFound().WithSpecificCase(orderNotFound).Build()).Case)
+}
+
+func TestCaseGuard_Register_NilCase(t *testing.T) {
+	g, err := NewCaseGuard(userNotFound, nil)
+	assert.Nil(t, g)
+	assert.NotNil(t, err)
+
+	g = mustNewCaseGuard(t)
+	assert.NotNil(t, g.Register(orderNotFound, nil))
+	assert.Equal(t, OtherCase, g.Labels(domainerr.NewNotFound().WithSpecificCase(orderNotFound).Build()).Case)
+}
+
+func mustNewCaseGuard(t *testing.T, cases ...domainerr.Case) *CaseGuard {
+	g, err := NewCaseGuard(cases...)
+	assert.Nil(t, err)
+	return g
+}
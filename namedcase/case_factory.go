@@ -0,0 +1,256 @@
+package namedcase
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ikonglong/domainerr"
+)
+
+var (
+	// nameRegexp matches snake_case names, e.g., purchase_limit_exceeded.
+	nameRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	// namespaceRegexp matches dot-separated snake_case names, e.g., order or payment.card.
+	namespaceRegexp = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*(\.[a-z][a-z0-9]*(_[a-z0-9]+)*)*$`)
+)
+
+// CaseFactory creates NamedCase in a namespace, and guarantees that the identifiers of the cases
+// created are unique in the process, even across nested namespaces, e.g., the child x of the case
+// card of payment and the case x of payment.card. There can be only one CaseFactory per namespace
+// in a process. It is safe for concurrent use.
+type CaseFactory struct {
+	namespace string
+
+	mu    sync.Mutex
+	cases map[string]*NamedCase
+}
+
+// factories holds the CaseFactory of each namespace.
+var factories sync.Map
+
+// identifiers holds the identifiers of all the cases created by the factories, so that two
+// factories can't create cases with the same identifier.
+var identifiers sync.Map
+
+// NewFactory creates a CaseFactory for the given namespace, which consists of one or more
+// snake_case segments separated by '.', e.g., order or payment.card. It fails if a CaseFactory has
+// been created for the namespace, so the factory should be kept in a package-level variable.
+func NewFactory(namespace string) (*CaseFactory, error) {
+	err := domainerr.CheckArgument(namespaceRegexp.MatchString(namespace),
+		"namespace %q is not dot-separated snake_case", namespace)
+	if err != nil {
+		return nil, err
+	}
+	f := &CaseFactory{
+		namespace: namespace,
+		cases:     make(map[string]*NamedCase),
+	}
+	_, loaded := factories.LoadOrStore(namespace, f)
+	err = domainerr.CheckArgument(!loaded, "namespace %s already has a CaseFactory", namespace)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type CaseOpt func(c *NamedCase) error
+
+// WithDescription sets the description of a case.
+func WithDescription(desc string) CaseOpt {
+	return func(c *NamedCase) error {
+		desc = strings.TrimSpace(desc)
+		err := domainerr.CheckArgument(desc != "", "description is blank")
+		if err != nil {
+			return err
+		}
+		c.description = desc
+		return nil
+	}
+}
+
+// WithMessageTemplate sets the message template of a case, which is formatted by fmt.Sprintf. E.g.,
+// "purchase limit %d exceeded".
+func WithMessageTemplate(tmpl string) CaseOpt {
+	return func(c *NamedCase) error {
+		tmpl = strings.TrimSpace(tmpl)
+		err := domainerr.CheckArgument(tmpl != "", "message template is blank")
+		if err != nil {
+			return err
+		}
+		c.messageTemplate = tmpl
+		return nil
+	}
+}
+
+// WithSince sets the version in which a case was introduced. See domainerr.CaseMeta.SetSince.
+func WithSince(version string) CaseOpt {
+	return func(c *NamedCase) error {
+		return c.meta.SetSince(version)
+	}
+}
+
+// WithDeprecated marks a case as deprecated. See domainerr.CaseMeta.SetDeprecated.
+func WithDeprecated(replacement domainerr.Case) CaseOpt {
+	return func(c *NamedCase) error {
+		return c.meta.SetDeprecated(c.identifier, replacement)
+	}
+}
+
+// WithVisibility sets the visibility of a case. See domainerr.CaseMeta.SetVisibility.
+func WithVisibility(v domainerr.Visibility) CaseOpt {
+	return func(c *NamedCase) error {
+		return c.meta.SetVisibility(v)
+	}
+}
+
+// WithFault overrides the fault of the status code of a case. See domainerr.CaseMeta.SetFault.
+func WithFault(fault domainerr.Fault) CaseOpt {
+	return func(c *NamedCase) error {
+		return c.meta.SetFault(fault)
+	}
+}
+
+// WithSeverity overrides the severity of the status code of a case. See
+// domainerr.CaseMeta.SetSeverity.
+func WithSeverity(sev domainerr.Severity) CaseOpt {
+	return func(c *NamedCase) error {
+		return c.meta.SetSeverity(sev)
+	}
+}
+
+func (f *CaseFactory) Namespace() string {
+	return f.namespace
+}
+
+// Cases returns all the cases created by this factory, sorted by their identifiers.
+func (f *CaseFactory) Cases() []*NamedCase {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cases := make([]*NamedCase, 0, len(f.cases))
+	for _, c := range f.cases {
+		cases = append(cases, c)
+	}
+	sort.Slice(cases, func(i, j int) bool {
+		return cases[i].identifier < cases[j].identifier
+	})
+	return cases
+}
+
+// New creates a case that represents a more specific status of the given status code, which must
+// not be CodeOK.
+//
+// The arg name must be snake_case, and must not be used by other cases created by this factory.
+func (f *CaseFactory) New(statusCode domainerr.Code, name string, opts ...CaseOpt) (*NamedCase, error) {
+	err := domainerr.CheckArgument(statusCode != domainerr.CodeOK, "statusCode is %s", statusCode.Name())
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(nameRegexp.MatchString(name), "name %q is not snake_case", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.add(newNamedCase(f.namespace, name, statusCode), opts)
+}
+
+// NewChild creates a case that is a more specific case of parent, which must be created by this
+// factory. The child has the same status code as parent, and is identified by name prefixed with
+// the identifier of parent, e.g., payment.declined.insufficient_funds.
+//
+// The arg name must be snake_case, and must not be used by other children of parent.
+func (f *CaseFactory) NewChild(parent *NamedCase, name string, opts ...CaseOpt) (*NamedCase, error) {
+	err := domainerr.CheckArgument(parent != nil, "parent is nil")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(parent.factory == f,
+		"parent %s is not created by the factory of namespace %s", parent.identifier, f.namespace)
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(nameRegexp.MatchString(name), "name %q is not snake_case", name)
+	if err != nil {
+		return nil, err
+	}
+
+	c := newNamedCase(f.namespace, name, parent.statusCode)
+	c.identifier = parent.identifier + "." + name
+	c.parent = parent
+	return f.add(c, opts)
+}
+
+func (f *CaseFactory) add(c *NamedCase, opts []CaseOpt) (*NamedCase, error) {
+	for _, setOpt := range opts {
+		if err := setOpt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, loaded := identifiers.LoadOrStore(c.identifier, c)
+	err := domainerr.CheckArgument(!loaded, "case %s already exists", c.identifier)
+	if err != nil {
+		return nil, err
+	}
+	c.factory = f
+	f.cases[c.identifier] = c
+	return c, nil
+}
+
+// NewInvalidArgument creates a case that represents a more specific InvalidArgument status.
+func (f *CaseFactory) NewInvalidArgument(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeInvalidArgument, name, opts...)
+}
+
+// NewDeadlineExceeded creates a case that represents a more specific DeadlineExceeded status.
+func (f *CaseFactory) NewDeadlineExceeded(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeDeadlineExceeded, name, opts...)
+}
+
+// NewNotFound creates a case that represents a more specific NotFound status.
+func (f *CaseFactory) NewNotFound(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeNotFound, name, opts...)
+}
+
+// NewAlreadyExists creates a case that represents a more specific AlreadyExists status.
+func (f *CaseFactory) NewAlreadyExists(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeAlreadyExists, name, opts...)
+}
+
+// NewPermissionDenied creates a case that represents a more specific PermissionDenied status.
+func (f *CaseFactory) NewPermissionDenied(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodePermissionDenied, name, opts...)
+}
+
+// NewResourceExhausted creates a case that represents a more specific ResourceExhausted status.
+func (f *CaseFactory) NewResourceExhausted(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeResourceExhausted, name, opts...)
+}
+
+// NewFailedPrecondition creates a case that represents a more specific FailedPrecondition status.
+func (f *CaseFactory) NewFailedPrecondition(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeFailedPrecondition, name, opts...)
+}
+
+// NewAborted creates a case that represents a more specific Aborted status.
+func (f *CaseFactory) NewAborted(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeAborted, name, opts...)
+}
+
+// NewOutOfRange creates a case that represents a more specific OutOfRange status.
+func (f *CaseFactory) NewOutOfRange(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeOutOfRange, name, opts...)
+}
+
+// NewInternalError creates a case that represents a more specific InternalError status.
+func (f *CaseFactory) NewInternalError(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeInternalError, name, opts...)
+}
+
+// NewDataLoss creates a case that represents a more specific DataLoss status.
+func (f *CaseFactory) NewDataLoss(name string, opts ...CaseOpt) (*NamedCase, error) {
+	return f.New(domainerr.CodeDataLoss, name, opts...)
+}
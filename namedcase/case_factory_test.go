@@ -0,0 +1,209 @@
+package namedcase
+
+import (
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFactory(t *testing.T) {
+	for _, ns := range []string{"order", "payment.card", "user_profile.v2"} {
+		f := newTestFactory(t, ns)
+		assert.Equal(t, ns, f.Namespace())
+	}
+	for _, ns := range []string{"", "Order", "order.", ".order", "order..card", "order-card", "2order", "order_"} {
+		f, err := NewFactory(ns)
+		assert.Nil(t, f)
+		assert.NotNil(t, err, ns)
+	}
+}
+
+func TestCaseFactory_New(t *testing.T) {
+	f := newTestFactory(t, "order")
+	c, err := f.NewFailedPrecondition("purchase_limit_exceeded",
+		WithDescription(" The purchase limit of the user is exceeded "),
+		WithMessageTemplate("purchase limit %d exceeded"))
+	assert.Nil(t, err)
+	assert.Equal(t, "order.purchase_limit_exceeded", c.Identifier())
+	assert.Equal(t, "order", c.Namespace())
+	assert.Equal(t, "purchase_limit_exceeded", c.Name())
+	assert.Equal(t, domainerr.CodeFailedPrecondition, c.StatusCode())
+	assert.Equal(t, "The purchase limit of the user is exceeded", c.Description())
+	assert.Equal(t, "purchase limit %d exceeded", c.MessageTemplate())
+	assert.Equal(t, "purchase limit 3 exceeded", c.Message(3))
+
+	s := c.Status(3)
+	assert.Equal(t, domainerr.CodeFailedPrecondition, s.Code())
+	assert.Equal(t, c, s.SpecificCase())
+	assert.Equal(t, "purchase limit 3 exceeded", s.Message())
+}
+
+func TestCaseFactory_New_IllegalArgs(t *testing.T) {
+	f := newTestFactory(t, "order")
+
+	_, err := f.New(domainerr.CodeOK, "ok")
+	assert.Equal(t, "illegal argument: statusCode is OK", err.Error())
+	for _, name := range []string{"", "PurchaseLimit", "purchase-limit", "purchase__limit", "_purchase"} {
+		_, err = f.NewInvalidArgument(name)
+		assert.NotNil(t, err, name)
+	}
+	_, err = f.NewInvalidArgument("bad_item", WithDescription(" "))
+	assert.Equal(t, "illegal argument: description is blank", err.Error())
+	_, err = f.NewInvalidArgument("bad_item", WithMessageTemplate(""))
+	assert.Equal(t, "illegal argument: message template is blank", err.Error())
+	assert.Empty(t, f.Cases())
+}
+
+func TestCaseFactory_New_UniqueWithinNamespace(t *testing.T) {
+	f := newTestFactory(t, "order")
+	_, err := f.NewNotFound("item_not_found")
+	assert.Nil(t, err)
+	_, err = f.NewOutOfRange("item_not_found")
+	assert.Equal(t, "illegal argument: case order.item_not_found already exists", err.Error())
+
+	// the same name in another namespace is fine
+	other := newTestFactory(t, "inventory")
+	_, err = other.NewNotFound("item_not_found")
+	assert.Nil(t, err)
+}
+
+func TestCaseFactory_Cases(t *testing.T) {
+	f := newTestFactory(t, "order")
+	b, _ := f.NewAborted("b_case")
+	a, _ := f.NewDataLoss("a_case")
+	assert.Equal(t, []*NamedCase{a, b}, f.Cases())
+
+	c, _ := f.New(domainerr.CodeUnavailable, "c_case")
+	assert.Equal(t, domainerr.CodeUnavailable, c.StatusCode())
+	assert.Empty(t, c.Message())
+}
+
+func TestCaseFactory_NewChild(t *testing.T) {
+	f := newTestFactory(t, "payment")
+	declined, _ := f.NewFailedPrecondition("declined")
+	insufficientFunds, err := f.NewChild(declined, "insufficient_funds",
+		WithMessageTemplate("balance %d is insufficient"))
+	assert.Nil(t, err)
+	assert.Equal(t, "payment.declined.insufficient_funds", insufficientFunds.Identifier())
+	assert.Equal(t, "insufficient_funds", insufficientFunds.Name())
+	assert.Equal(t, domainerr.CodeFailedPrecondition, insufficientFunds.StatusCode())
+	assert.Equal(t, declined, insufficientFunds.Parent())
+	assert.Nil(t, declined.Parent())
+	assert.True(t, domainerr.CaseIsA(domainerr.NewError(insufficientFunds.Status(10)), declined))
+
+	_, err = f.NewChild(declined, "insufficient_funds")
+	assert.Equal(t, "illegal argument: case payment.declined.insufficient_funds already exists", err.Error())
+	// a root case with the same name is fine
+	_, err = f.NewFailedPrecondition("insufficient_funds")
+	assert.Nil(t, err)
+
+	_, err = f.NewChild(nil, "card_expired")
+	assert.Equal(t, "illegal argument: parent is nil", err.Error())
+	_, err = f.NewChild(declined, "Card")
+	assert.NotNil(t, err)
+	other := newTestFactory(t, "order")
+	_, err = other.NewChild(declined, "card_expired")
+	assert.Equal(t, "illegal argument: parent payment.declined is not created by the factory of namespace order",
+		err.Error())
+}
+
+func TestNewFactory_OnePerNamespace(t *testing.T) {
+	f := newTestFactory(t, "order")
+	_, err := f.NewNotFound("item_not_found")
+	assert.Nil(t, err)
+
+	other, err := NewFactory("order")
+	assert.Nil(t, other)
+	assert.Equal(t, "illegal argument: namespace order already has a CaseFactory", err.Error())
+	_, err = NewFactory("order.item")
+	assert.Nil(t, err)
+	factories.Delete("order.item")
+}
+
+// newTestFactory creates a CaseFactory for the given namespace, which is released when the test
+// finishes, so that tests can reuse namespaces.
+func newTestFactory(t *testing.T, namespace string) *CaseFactory {
+	f, err := NewFactory(namespace)
+	assert.Nil(t, err)
+	t.Cleanup(func() {
+		factories.Delete(namespace)
+		for _, c := range f.Cases() {
+			identifiers.Delete(c.Identifier())
+		}
+	})
+	return f
+}
+
+func TestCaseFactory_NestedNamespaceCollision(t *testing.T) {
+	payment := newTestFactory(t, "payment")
+	card, err := payment.NewFailedPrecondition("card")
+	assert.Nil(t, err)
+	_, err = payment.NewChild(card, "expired")
+	assert.Nil(t, err)
+
+	paymentCard := newTestFactory(t, "payment.card")
+	_, err = paymentCard.NewFailedPrecondition("expired")
+	assert.Equal(t, "illegal argument: case payment.card.expired already exists", err.Error())
+	_, err = paymentCard.NewFailedPrecondition("blocked")
+	assert.Nil(t, err)
+	_, err = payment.NewChild(card, "blocked")
+	assert.Equal(t, "illegal argument: case payment.card.blocked already exists", err.Error())
+}
+
+func TestCaseFactory_Lifecycle(t *testing.T) {
+	f := newTestFactory(t, "order")
+	inventory, err := f.NewFailedPrecondition("insufficient_inventory", WithSince("v1.2.0"))
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.Lifecycle{Since: "v1.2.0", Visibility: domainerr.VisibilityPublic}, inventory.Lifecycle())
+
+	outOfStock, err := f.NewFailedPrecondition("out_of_stock", WithDeprecated(inventory),
+		WithVisibility(domainerr.VisibilityInternal))
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.Lifecycle{
+		Deprecated: true, Replacement: inventory, Visibility: domainerr.VisibilityInternal,
+	}, outOfStock.Lifecycle())
+	assert.Equal(t, "case order.out_of_stock is deprecated, use order.insufficient_inventory instead",
+		domainerr.DeprecationNotice(outOfStock))
+
+	noReplacement, err := f.NewFailedPrecondition("sold_out", WithDeprecated(nil))
+	assert.Nil(t, err)
+	assert.True(t, noReplacement.Lifecycle().Deprecated)
+	assert.Nil(t, noReplacement.Lifecycle().Replacement)
+
+	_, err = f.NewNotFound("order_missing", WithSince(" "))
+	assert.Equal(t, "illegal argument: version is blank", err.Error())
+	_, err = f.NewNotFound("order_missing", WithVisibility("secret"))
+	assert.Equal(t, `illegal argument: unknown visibility "secret"`, err.Error())
+	_, err = f.NewNotFound("order_missing", WithDeprecated(testCase("order.order_missing")))
+	assert.Equal(t, "illegal argument: case order.order_missing is replaced by itself", err.Error())
+}
+
+type testCase string
+
+func (c testCase) Identifier() string {
+	return string(c)
+}
+
+func (c testCase) StatusCode() domainerr.Code {
+	return domainerr.CodeNotFound
+}
+
+func TestCaseFactory_FaultAndSeverity(t *testing.T) {
+	f := newTestFactory(t, "payment")
+	rejected, err := f.NewFailedPrecondition("gateway_rejected", WithFault(domainerr.DependencyFault),
+		WithSeverity(domainerr.SeverityWarning))
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.DependencyFault, rejected.Status().Fault())
+	assert.Equal(t, domainerr.SeverityWarning, rejected.Status().Severity())
+
+	declined, _ := f.NewFailedPrecondition("declined")
+	assert.Equal(t, domainerr.ClientFault, declined.Status().Fault())
+	assert.Equal(t, domainerr.SeverityInfo, declined.Status().Severity())
+
+	_, err = f.NewFailedPrecondition("expired", WithFault(domainerr.NoFault))
+	assert.Equal(t, `illegal argument: illegal fault "none"`, err.Error())
+	_, err = f.NewFailedPrecondition("expired", WithSeverity(0))
+	assert.Equal(t, "illegal argument: illegal severity 0", err.Error())
+}
@@ -0,0 +1,98 @@
+package namedcase
+
+import (
+	"fmt"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// NamedCase is a case identified by a descriptive name prefixed with a namespace, e.g.,
+// order.purchase_limit_exceeded. A child case is identified by the name prefixed with the
+// identifier of its parent, e.g., payment.declined.insufficient_funds.
+type NamedCase struct {
+	namespace  string
+	name       string
+	identifier string
+	statusCode domainerr.Code
+	parent     *NamedCase
+	// factory is the CaseFactory which created this case.
+	factory *CaseFactory
+
+	description     string
+	messageTemplate string
+	meta            domainerr.CaseMeta
+}
+
+func newNamedCase(namespace string, name string, statusCode domainerr.Code) *NamedCase {
+	return &NamedCase{
+		namespace:  namespace,
+		name:       name,
+		identifier: namespace + "." + name,
+		statusCode: statusCode,
+	}
+}
+
+// Identifier returns the name prefixed with the namespace, or with the identifier of the parent,
+// e.g., order.purchase_limit_exceeded.
+func (c *NamedCase) Identifier() string {
+	return c.identifier
+}
+
+func (c *NamedCase) StatusCode() domainerr.Code {
+	return c.statusCode
+}
+
+func (c *NamedCase) Namespace() string {
+	return c.namespace
+}
+
+func (c *NamedCase) Name() string {
+	return c.name
+}
+
+// Parent returns the parent case, or nil if this case is a root.
+func (c *NamedCase) Parent() domainerr.Case {
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent
+}
+
+func (c *NamedCase) Description() string {
+	return c.description
+}
+
+func (c *NamedCase) MessageTemplate() string {
+	return c.messageTemplate
+}
+
+// Lifecycle returns the lifecycle metadata of this case. The Visibility is never empty.
+func (c *NamedCase) Lifecycle() domainerr.Lifecycle {
+	return c.meta.Lifecycle()
+}
+
+// Fault returns the fault overriding the one of the status code, or "" if it isn't overridden.
+func (c *NamedCase) Fault() domainerr.Fault {
+	return c.meta.Fault()
+}
+
+// Severity returns the severity overriding the one of the status code, or 0 if it isn't
+// overridden.
+func (c *NamedCase) Severity() domainerr.Severity {
+	return c.meta.Severity()
+}
+
+// Message formats the message template with the given args. It returns "" if this case has no
+// message template.
+func (c *NamedCase) Message(args ...any) string {
+	if c.messageTemplate == "" {
+		return ""
+	}
+	return fmt.Sprintf(c.messageTemplate, args...)
+}
+
+// Status returns a status with the status code and this case, whose message is formatted from the
+// message template with the given args.
+func (c *NamedCase) Status(args ...any) *domainerr.Status {
+	return domainerr.NewWithCode(c.statusCode).WithCaseAndMsg(c, c.Message(args...))
+}
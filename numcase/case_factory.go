@@ -1,9 +1,6 @@
 package numcase
 
 import (
-	"bytes"
-	"math"
-	"sort"
 	"strconv"
 
 	"github.com/ikonglong/domainerr"
@@ -13,6 +10,9 @@ type CaseFactory struct {
 	codingStrategy *CodingStrategy
 	appCode        int
 	moduleCode     int
+	appCase        *GroupCase
+	moduleCase     *GroupCase
+	catalog        *Catalog
 }
 
 type FactoryOpt func(f *CaseFactory) error
@@ -39,6 +39,58 @@ func WithModuleCode(moduleCode int) FactoryOpt {
 	}
 }
 
+// WithCatalog records the cases created by the factory in the given catalog, which rejects case
+// codes already used in the module. The app and the module of the factory must be claimed in the
+// catalog, if the coding strategy has such codes.
+func WithCatalog(catalog *Catalog) FactoryOpt {
+	return func(f *CaseFactory) error {
+		err := domainerr.CheckArgument(catalog != nil, "catalog is nil")
+		if err != nil {
+			return err
+		}
+		f.catalog = catalog
+		return nil
+	}
+}
+
+type CaseOpt func(c *NumCase) error
+
+// WithSince sets the version in which a case was introduced. See domainerr.CaseMeta.SetSince.
+func WithSince(version string) CaseOpt {
+	return func(c *NumCase) error {
+		return c.meta.SetSince(version)
+	}
+}
+
+// WithDeprecated marks a case as deprecated. See domainerr.CaseMeta.SetDeprecated.
+func WithDeprecated(replacement domainerr.Case) CaseOpt {
+	return func(c *NumCase) error {
+		return c.meta.SetDeprecated(c.identifier, replacement)
+	}
+}
+
+// WithVisibility sets the visibility of a case. See domainerr.CaseMeta.SetVisibility.
+func WithVisibility(v domainerr.Visibility) CaseOpt {
+	return func(c *NumCase) error {
+		return c.meta.SetVisibility(v)
+	}
+}
+
+// WithFault overrides the fault of the status code of a case. See domainerr.CaseMeta.SetFault.
+func WithFault(fault domainerr.Fault) CaseOpt {
+	return func(c *NumCase) error {
+		return c.meta.SetFault(fault)
+	}
+}
+
+// WithSeverity overrides the severity of the status code of a case. See
+// domainerr.CaseMeta.SetSeverity.
+func WithSeverity(sev domainerr.Severity) CaseOpt {
+	return func(c *NumCase) error {
+		return c.meta.SetSeverity(sev)
+	}
+}
+
 func NewFactory(codingStrategy *CodingStrategy, opts ...FactoryOpt) (*CaseFactory, error) {
 	err := domainerr.CheckArgument(codingStrategy != nil, "codingStrategy is nil")
 	if err != nil {
@@ -65,17 +117,40 @@ func NewFactory(codingStrategy *CodingStrategy, opts ...FactoryOpt) (*CaseFactor
 		return nil, err
 	}
 
+	if f.catalog != nil {
+		if err = f.catalog.checkClaimed(f); err != nil {
+			return nil, err
+		}
+	}
+
+	f.appCase, f.moduleCase = codingStrategy.groupCasesOf(f.appCode, f.moduleCode)
 	return f, nil
 }
 
+// AppCase returns the GroupCase of the app of this factory, or nil if the coding strategy has no
+// app code.
+func (f *CaseFactory) AppCase() *GroupCase {
+	return f.appCase
+}
+
+// ModuleCase returns the GroupCase of the module of this factory, or nil if the coding strategy
+// has no module code.
+func (f *CaseFactory) ModuleCase() *GroupCase {
+	return f.moduleCase
+}
+
+func (f *CaseFactory) parentCase() *GroupCase {
+	return parentCaseOf(f.appCase, f.moduleCase)
+}
+
 // NewInvalidArgument creates a case that represents a more specific InvalidArgument status.
 //
 // The arg caseCode must be in the code segment corresponding to InvalidArgument status.
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // InvalidArgument status is [1, 50].
-func (f *CaseFactory) NewInvalidArgument(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeInvalidArgument, caseCode)
+func (f *CaseFactory) NewInvalidArgument(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeInvalidArgument, caseCode, opts)
 }
 
 // NewDeadlineExceeded creates a case that represents a more specific DeadlineExceeded status.
@@ -84,8 +159,8 @@ func (f *CaseFactory) NewInvalidArgument(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // DeadlineExceeded status is [51, 100].
-func (f *CaseFactory) NewDeadlineExceeded(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeDeadlineExceeded, caseCode)
+func (f *CaseFactory) NewDeadlineExceeded(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeDeadlineExceeded, caseCode, opts)
 }
 
 // NewNotFound creates a case that represents a more specific NotFound status.
@@ -94,8 +169,8 @@ func (f *CaseFactory) NewDeadlineExceeded(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // NotFound status is [101, 150].
-func (f *CaseFactory) NewNotFound(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeNotFound, caseCode)
+func (f *CaseFactory) NewNotFound(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeNotFound, caseCode, opts)
 }
 
 // NewAlreadyExists creates a case that represents a more specific AlreadyExists status.
@@ -104,8 +179,8 @@ func (f *CaseFactory) NewNotFound(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // AlreadyExists status is [151, 200].
-func (f *CaseFactory) NewAlreadyExists(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeAlreadyExists, caseCode)
+func (f *CaseFactory) NewAlreadyExists(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeAlreadyExists, caseCode, opts)
 }
 
 // NewPermissionDenied creates a case that represents a more specific PermissionDenied status.
@@ -114,8 +189,8 @@ func (f *CaseFactory) NewAlreadyExists(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // PermissionDenied status is [201, 250].
-func (f *CaseFactory) NewPermissionDenied(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodePermissionDenied, caseCode)
+func (f *CaseFactory) NewPermissionDenied(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodePermissionDenied, caseCode, opts)
 }
 
 // NewResourceExhausted creates a case that represents a more specific ResourceExhausted status.
@@ -124,8 +199,8 @@ func (f *CaseFactory) NewPermissionDenied(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // ResourceExhausted status is [251, 300].
-func (f *CaseFactory) NewResourceExhausted(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeResourceExhausted, caseCode)
+func (f *CaseFactory) NewResourceExhausted(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeResourceExhausted, caseCode, opts)
 }
 
 // NewFailedPrecondition creates a case that represents a more specific FailedPrecondition status.
@@ -134,8 +209,8 @@ func (f *CaseFactory) NewResourceExhausted(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // FailedPrecondition status is [301, 350].
-func (f *CaseFactory) NewFailedPrecondition(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeFailedPrecondition, caseCode)
+func (f *CaseFactory) NewFailedPrecondition(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeFailedPrecondition, caseCode, opts)
 }
 
 // NewAborted creates a case that represents a more specific Aborted status.
@@ -144,8 +219,8 @@ func (f *CaseFactory) NewFailedPrecondition(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // Aborted status is [351, 400].
-func (f *CaseFactory) NewAborted(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeAborted, caseCode)
+func (f *CaseFactory) NewAborted(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeAborted, caseCode, opts)
 }
 
 // NewOutOfRange creates a case that represents a more specific OutOfRange status.
@@ -154,8 +229,8 @@ func (f *CaseFactory) NewAborted(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // OutOfRange status is [401, 450].
-func (f *CaseFactory) NewOutOfRange(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeOutOfRange, caseCode)
+func (f *CaseFactory) NewOutOfRange(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeOutOfRange, caseCode, opts)
 }
 
 // NewInternalError creates a case that represents a more specific InternalError status.
@@ -164,8 +239,8 @@ func (f *CaseFactory) NewOutOfRange(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // InternalError status is [451, 500].
-func (f *CaseFactory) NewInternalError(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeInternalError, caseCode)
+func (f *CaseFactory) NewInternalError(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeInternalError, caseCode, opts)
 }
 
 // NewDataLoss creates a case that represents a more specific DataLoss status.
@@ -174,11 +249,67 @@ func (f *CaseFactory) NewInternalError(caseCode int) (*NumCase, error) {
 //
 // If the out-of-box DefaultCodeMapper is used, the code segment corresponding to
 // DataLoss status is [501, 550].
-func (f *CaseFactory) NewDataLoss(caseCode int) (*NumCase, error) {
-	return f.create(domainerr.CodeDataLoss, caseCode)
+func (f *CaseFactory) NewDataLoss(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeDataLoss, caseCode, opts)
+}
+
+// NewCancelled creates a case that represents a more specific Cancelled status.
+//
+// The arg caseCode must be in the code segment corresponding to Cancelled status. The out-of-box
+// DefaultCodeMapper doesn't map Cancelled status, so the CodeMapper must override Cancelled().
+func (f *CaseFactory) NewCancelled(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeCancelled, caseCode, opts)
+}
+
+// NewUnknownError creates a case that represents a more specific Unknown status.
+//
+// The arg caseCode must be in the code segment corresponding to Unknown status. The out-of-box
+// DefaultCodeMapper doesn't map Unknown status, so the CodeMapper must override Unknown().
+func (f *CaseFactory) NewUnknownError(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeUnknown, caseCode, opts)
+}
+
+// NewUnauthenticated creates a case that represents a more specific Unauthenticated status.
+//
+// The arg caseCode must be in the code segment corresponding to Unauthenticated status. The out-of-box
+// DefaultCodeMapper doesn't map Unauthenticated status, so the CodeMapper must override Unauthenticated().
+func (f *CaseFactory) NewUnauthenticated(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeUnauthenticated, caseCode, opts)
+}
+
+// NewUnimplemented creates a case that represents a more specific Unimplemented status.
+//
+// The arg caseCode must be in the code segment corresponding to Unimplemented status. The out-of-box
+// DefaultCodeMapper doesn't map Unimplemented status, so the CodeMapper must override Unimplemented().
+func (f *CaseFactory) NewUnimplemented(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeUnimplemented, caseCode, opts)
+}
+
+// NewUnavailable creates a case that represents a more specific Unavailable status.
+//
+// The arg caseCode must be in the code segment corresponding to Unavailable status. The out-of-box
+// DefaultCodeMapper doesn't map Unavailable status, so the CodeMapper must override Unavailable().
+func (f *CaseFactory) NewUnavailable(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeUnavailable, caseCode, opts)
+}
+
+// NewUndefined creates a case that represents a more specific Undefined status.
+//
+// The arg caseCode must be in the code segment corresponding to Undefined status. The out-of-box
+// DefaultCodeMapper doesn't map Undefined status, so the CodeMapper must override Undefined().
+func (f *CaseFactory) NewUndefined(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeUndefined, caseCode, opts)
 }
 
-func (f *CaseFactory) create(statusCode domainerr.Code, caseCode int) (*NumCase, error) {
+// NewAuthorizationExpired creates a case that represents a more specific AuthorizationExpired status.
+//
+// The arg caseCode must be in the code segment corresponding to AuthorizationExpired status. The out-of-box
+// DefaultCodeMapper doesn't map AuthorizationExpired status, so the CodeMapper must override AuthorizationExpired().
+func (f *CaseFactory) NewAuthorizationExpired(caseCode int, opts ...CaseOpt) (*NumCase, error) {
+	return f.create(domainerr.CodeAuthorizationExpired, caseCode, opts)
+}
+
+func (f *CaseFactory) create(statusCode domainerr.Code, caseCode int, opts []CaseOpt) (*NumCase, error) {
 	codeSeg := f.codingStrategy.statusCodeMapper.CaseCodeSegmentFor(statusCode)
 	err := domainerr.CheckArgument(codeSeg != nil,
 		"statusCodeMapper doesn't define a CaseCodeSegment for status code %s", statusCode.String())
@@ -192,31 +323,22 @@ func (f *CaseFactory) create(statusCode domainerr.Code, caseCode int) (*NumCase,
 		return nil, err
 	}
 
-	var caseID bytes.Buffer
-	if f.codingStrategy.numDigitsOfAppCode > 0 {
-		caseID.WriteString(f.padLeftZeros(f.appCode, f.codingStrategy.numDigitsOfAppCode))
-		caseID.WriteByte('_')
+	c := f.codingStrategy.newCase(f.appCode, f.moduleCode, caseCode, statusCode, f.parentCase())
+	for _, setOpt := range opts {
+		if err = setOpt(c); err != nil {
+			return nil, err
+		}
 	}
-	if f.codingStrategy.numDigitsOfModuleCode > 0 {
-		caseID.WriteString(f.padLeftZeros(f.moduleCode, f.codingStrategy.numDigitsOfModuleCode))
-		caseID.WriteByte('_')
+	if f.catalog != nil {
+		if err = f.catalog.register(c); err != nil {
+			return nil, err
+		}
 	}
-	caseID.WriteString(f.padLeftZeros(caseCode, f.codingStrategy.numDigitsOfCaseCode))
-	return newNumCase(f.appCode, f.moduleCode, caseCode, caseID.String(), statusCode), nil
+	return c, nil
 }
 
 func (f *CaseFactory) padLeftZeros(num int, minLen int) string {
-	s := strconv.Itoa(num)
-	if len(s) >= minLen {
-		return s
-	}
-
-	var buf bytes.Buffer
-	for i := len(s); i < minLen; i++ {
-		buf.WriteByte('0')
-	}
-	buf.WriteString(s)
-	return buf.String()
+	return padLeftZeros(strconv.Itoa(num), minLen)
 }
 
 type CodingStrategy struct {
@@ -229,6 +351,84 @@ type CodingStrategy struct {
 	numDigitsOfCaseCode int
 	caseCodeRange       *NumRange
 	statusCodeMapper    CodeMapper
+
+	identifierFormat *IdentifierFormat
+	warnings         []string
+}
+
+// Warnings returns the problems found by CodingStrategyBuilder.Build which don't make the coding
+// strategy invalid, e.g., case codes between segments which are not mapped to any status code.
+func (s *CodingStrategy) Warnings() []string {
+	return s.warnings
+}
+
+// IdentifierFormat returns the format of the identifiers of the cases of this coding strategy.
+func (s *CodingStrategy) IdentifierFormat() *IdentifierFormat {
+	return s.identifierFormat
+}
+
+func (s *CodingStrategy) newCase(appCode int, moduleCode int, caseCode int, statusCode domainerr.Code,
+	parent *GroupCase) *NumCase {
+	identifier := s.identifierFormat.format(s, appCode, moduleCode, caseCode)
+	c := newNumCase(appCode, moduleCode, caseCode, identifier, statusCode, parent)
+	c.packed = s.pack(appCode, moduleCode, caseCode)
+	return c
+}
+
+// groupCasesOf returns the GroupCase of the given app and module.
+func (s *CodingStrategy) groupCasesOf(appCode int, moduleCode int) (appCase *GroupCase, moduleCase *GroupCase) {
+	appID, moduleID := s.identifierFormat.formatGroups(s, appCode, moduleCode)
+	if appID != "" {
+		appCase = &GroupCase{identifier: appID}
+	}
+	if moduleID != "" {
+		moduleCase = &GroupCase{identifier: moduleID, parent: appCase}
+	}
+	return appCase, moduleCase
+}
+
+// pack packs the given codes into an integer, i.e.,
+// appCode*radix^(m+c) + moduleCode*radix^c + caseCode, where m and c are the numbers of digits of
+// the module code and the case code.
+func (s *CodingStrategy) pack(appCode int, moduleCode int, caseCode int) int64 {
+	radix := s.identifierFormat.radix
+	moduleUnit := intPow(radix, s.numDigitsOfModuleCode)
+	caseUnit := intPow(radix, s.numDigitsOfCaseCode)
+	return (int64(appCode)*moduleUnit+int64(moduleCode))*caseUnit + int64(caseCode)
+}
+
+// Parse parses the given identifier of a NumCase created with this coding strategy, e.g.,
+// 01_002_0105, which is the inverse of CaseFactory.NewXxx. The identifier is parsed according to
+// the IdentifierFormat, and the status code is recovered from the CaseCodeSegment including the
+// case code.
+//
+// It returns an error if the identifier is malformed, or the case code isn't in any
+// CaseCodeSegment of the statusCodeMapper.
+func (s *CodingStrategy) Parse(identifier string) (*NumCase, error) {
+	appCode, moduleCode, caseCode, err := s.identifierFormat.parse(s, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	statusCode, found := s.statusCodeOf(caseCode)
+	err = domainerr.CheckArgument(found,
+		"case code %d of identifier %q isn't in any CaseCodeSegment", caseCode, identifier)
+	if err != nil {
+		return nil, err
+	}
+	appCase, moduleCase := s.groupCasesOf(appCode, moduleCode)
+	return s.newCase(appCode, moduleCode, caseCode, statusCode, parentCaseOf(appCase, moduleCase)), nil
+}
+
+// statusCodeOf returns the status code mapped to the CaseCodeSegment including the given case code.
+// There is at most one, since Build rejects overlapping segments.
+func (s *CodingStrategy) statusCodeOf(caseCode int) (domainerr.Code, bool) {
+	for statusCode, seg := range s.statusCodeMapper.Mappings() {
+		if seg != nil && seg.include(caseCode) {
+			return statusCode, true
+		}
+	}
+	return domainerr.Code{}, false
 }
 
 type CodingStrategyBuilder struct {
@@ -261,6 +461,16 @@ func (b *CodingStrategyBuilder) StatusCodeMapper(m CodeMapper) *CodingStrategyBu
 	return b
 }
 
+// IdentifierFormat sets the format of identifiers, which is DefaultIdentifierFormat by default.
+func (b *CodingStrategyBuilder) IdentifierFormat(f *IdentifierFormat) *CodingStrategyBuilder {
+	b.s.identifierFormat = f
+	return b
+}
+
+// Build validates the settings and builds the CodingStrategy. The segments of the statusCodeMapper
+// must be inside the case code range and must not overlap with each other, otherwise all the
+// segments out of the range and all the overlaps are reported together in a *ValidationError. Case codes between segments that are not mapped to
+// any status code are reported as CodingStrategy.Warnings.
 func (b *CodingStrategyBuilder) Build() (*CodingStrategy, error) {
 	err := domainerr.CheckArgument(b.s.numDigitsOfAppCode >= 0, "numDigitsOfAppCode < 0")
 	if err != nil {
@@ -279,21 +489,34 @@ func (b *CodingStrategyBuilder) Build() (*CodingStrategy, error) {
 		return nil, err
 	}
 
-	b.s.appCodeRange, _ = NewNumRange(0, domainerr.FloatToInt(math.Pow10(b.s.numDigitsOfAppCode))-1)
-	b.s.moduleCodeRange, _ = NewNumRange(0, domainerr.FloatToInt(math.Pow10(b.s.numDigitsOfModuleCode))-1)
-	b.s.caseCodeRange, _ = NewNumRange(0, domainerr.FloatToInt(math.Pow10(b.s.numDigitsOfCaseCode))-1)
-
-	segs := b.s.statusCodeMapper.CaseCodeSegments()
-	sort.Slice(segs, func(i, j int) bool {
-		return segs[i].End() <= segs[j].Start()
-	})
-	for _, cs := range segs {
-		err = domainerr.CheckArgument(b.s.caseCodeRange.includeRange(cs),
-			"case code range %v of CodingStrategy doesn't include caseCodeSegment %v defined by CodeMapper",
-			b.s.caseCodeRange, cs)
-		if err != nil {
-			return nil, err
+	if b.s.identifierFormat == nil {
+		b.s.identifierFormat = DefaultIdentifierFormat
+	}
+	radix := b.s.identifierFormat.radix
+	numDigits := b.s.numDigitsOfAppCode + b.s.numDigitsOfModuleCode + b.s.numDigitsOfCaseCode
+	err = domainerr.CheckArgument(intPow(radix, numDigits) > 0,
+		"%d digits in radix %d overflow int64", numDigits, radix)
+	if err != nil {
+		return nil, err
+	}
+
+	b.s.appCodeRange, _ = NewNumRange(0, int(intPow(radix, b.s.numDigitsOfAppCode))-1)
+	b.s.moduleCodeRange, _ = NewNumRange(0, int(intPow(radix, b.s.numDigitsOfModuleCode))-1)
+	b.s.caseCodeRange, _ = NewNumRange(0, int(intPow(radix, b.s.numDigitsOfCaseCode))-1)
+
+	verr := &ValidationError{}
+	mappings := b.s.statusCodeMapper.Mappings()
+	for _, statusCode := range sortedCodes(mappings) {
+		if seg := mappings[statusCode]; !b.s.caseCodeRange.includeRange(seg) {
+			verr.addf("segment %s for %s isn't inside case code range %s",
+				seg.String(), statusCode.Name(), b.s.caseCodeRange.String())
 		}
 	}
+	overlaps, gaps := checkSegments(mappings)
+	verr.Problems = append(verr.Problems, overlaps...)
+	if err = verr.orNil(); err != nil {
+		return nil, err
+	}
+	b.s.warnings = gaps
 	return b.s, nil
 }
@@ -84,7 +84,7 @@ func TestNewFactory_OK(t *testing.T) {
 
 func TestCaseFactory_Create_CodeMapperNotDefineMappingForGivenStatusCode(t *testing.T) {
 	f, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(1))
-	_, err := f.create(domainerr.CodeUnknown, 551) // 100 is start of case code segment for CodeUnknown
+	_, err := f.create(domainerr.CodeUnknown, 551, nil) // 100 is start of case code segment for CodeUnknown
 	assert.NotNil(t, err)
 	assert.Equal(t, fmt.Sprintf(
 		"illegal argument: statusCodeMapper doesn't define a CaseCodeSegment for status code %s",
@@ -394,7 +394,7 @@ func TestCaseFactory_Create(t *testing.T) {
 		NumDigitsOfCaseCode(3).
 		StatusCodeMapper(NewCodeMapper(&DefaultCodeMapper{})).Build()
 	f, _ := NewFactory(strategy, WithAppCode(1), WithModuleCode(1))
-	c, err := f.create(domainerr.CodeInvalidArgument, 1)
+	c, err := f.create(domainerr.CodeInvalidArgument, 1, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, c)
 	assert.Equal(t, 1, c.appCode)
@@ -412,8 +412,151 @@ func TestCaseFactory_PadLeftZeros(t *testing.T) {
 	assert.Equal(t, "1000", f.padLeftZeros(1000, 3))
 }
 
+func TestCaseFactory_GroupCases(t *testing.T) {
+	f, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2))
+	assert.Equal(t, "1", f.AppCase().Identifier())
+	assert.Equal(t, domainerr.CodeUnknown, f.AppCase().StatusCode())
+	assert.Nil(t, f.AppCase().Parent())
+	assert.Equal(t, "1_2", f.ModuleCase().Identifier())
+	assert.Equal(t, f.AppCase(), f.ModuleCase().Parent())
+
+	c, _ := f.NewNotFound(101)
+	assert.Equal(t, f.ModuleCase(), c.Parent())
+	err := domainerr.NewNotFound().WithSpecificCase(c).Build()
+	assert.True(t, domainerr.CaseIsA(err, f.ModuleCase()))
+	assert.True(t, domainerr.CaseIsA(err, f.AppCase()))
+
+	other, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(3))
+	assert.False(t, domainerr.CaseIsA(err, other.ModuleCase()))
+	assert.True(t, domainerr.CaseIsA(err, other.AppCase()))
+
+	f, _ = NewFactory(csWithoutAppCodeAndModuleCode)
+	assert.Nil(t, f.AppCase())
+	assert.Nil(t, f.ModuleCase())
+	c, _ = f.NewNotFound(101)
+	assert.Nil(t, c.Parent())
+}
+
+func TestCaseFactory_NewOptionallyMapped(t *testing.T) {
+	strategy, _ := NewCodingStrategyBuilder().
+		NumDigitsOfCaseCode(3).
+		StatusCodeMapper(NewCodeMapper(&mapperWithOptionalSegments{})).Build()
+	f, _ := NewFactory(strategy)
+
+	c, err := f.NewUnavailable(551)
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.CodeUnavailable, c.StatusCode())
+	c, err = f.NewUndefined(650)
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.CodeUndefined, c.StatusCode())
+	_, err = f.NewUnavailable(601)
+	assert.NotNil(t, err)
+
+	for _, newCase := range []func(int, ...CaseOpt) (*NumCase, error){
+		f.NewCancelled, f.NewUnknownError, f.NewUnauthenticated, f.NewUnimplemented, f.NewAuthorizationExpired,
+	} {
+		_, err = newCase(700)
+		assert.Contains(t, err.Error(), "statusCodeMapper doesn't define a CaseCodeSegment for status code")
+	}
+}
+
 // Tests for CaseFactory end
 
+// Tests for CodingStrategy start
+
+type overlappingCodeMapper struct {
+	DefaultCodeMapper
+}
+
+func (m *overlappingCodeMapper) NotFound() *CaseCodeSegment {
+	r, _ := NewNumRange(40, 150)
+	return r
+}
+
+func (m *overlappingCodeMapper) DataLoss() *CaseCodeSegment {
+	r, _ := NewNumRange(601, 650)
+	return r
+}
+
+func TestCodingStrategyBuilder_Build_Overlaps(t *testing.T) {
+	_, err := NewCodingStrategyBuilder().
+		NumDigitsOfCaseCode(3).
+		StatusCodeMapper(NewCodeMapper(&overlappingCodeMapper{})).Build()
+	assert.Equal(t, &ValidationError{Problems: []string{
+		"segment [1, 50] for InvalidArgument overlaps segment [40, 150] for NotFound in [40, 50]",
+		"segment [51, 100] for DeadlineExceeded overlaps segment [40, 150] for NotFound in [51, 100]",
+	}}, err)
+}
+
+func TestCodingStrategyBuilder_Build_Gaps(t *testing.T) {
+	assert.Empty(t, csWith1DigitAppCodeAndModuleCode.Warnings())
+
+	m, _ := NewCodeMapperFromSegments(map[domainerr.Code]NumRange{
+		domainerr.CodeInvalidArgument: numRange(1, 100),
+		domainerr.CodeNotFound:        numRange(201, 300),
+		domainerr.CodeInternalError:   numRange(301, 400),
+		domainerr.CodeDataLoss:        numRange(901, 999),
+	})
+	strategy, err := NewCodingStrategyBuilder().NumDigitsOfCaseCode(3).StatusCodeMapper(m).Build()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"case codes [101, 200] between segment [1, 100] for InvalidArgument and segment [201, 300] for NotFound are unmapped",
+		"case codes [401, 900] between segment [301, 400] for InternalError and segment [901, 999] for DataLoss are unmapped",
+	}, strategy.Warnings())
+}
+
+func TestCodingStrategy_Parse(t *testing.T) {
+	f, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2))
+	for _, newCase := range []func(int, ...CaseOpt) (*NumCase, error){f.NewInvalidArgument, f.NewNotFound, f.NewDataLoss} {
+		for _, caseCode := range []int{1, 105, 550} {
+			want, err := newCase(caseCode)
+			if err != nil {
+				continue
+			}
+			got, err := csWith1DigitAppCodeAndModuleCode.Parse(want.Identifier())
+			assert.Nil(t, err)
+			assert.Equal(t, want, got)
+		}
+	}
+
+	c, err := csWith1DigitAppCodeAndModuleCode.Parse("1_2_105")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, c.AppCode())
+	assert.Equal(t, 2, c.ModuleCode())
+	assert.Equal(t, 105, c.CaseCode())
+	assert.Equal(t, domainerr.CodeNotFound, c.StatusCode())
+	assert.Equal(t, "1_2", c.Parent().Identifier())
+
+	c, err = csWithoutAppCodeAndModuleCode.Parse("301")
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.CodeFailedPrecondition, c.StatusCode())
+	assert.Equal(t, 0, c.AppCode())
+	assert.Nil(t, c.Parent())
+}
+
+func TestCodingStrategy_Parse_Illegal(t *testing.T) {
+	_, err := csWith1DigitAppCodeAndModuleCode.Parse("1_105")
+	assert.Equal(t, `illegal argument: identifier "1_105" doesn't consist of 3 parts separated by "_"`, err.Error())
+
+	_, err = csWith1DigitAppCodeAndModuleCode.Parse("1_2_15")
+	assert.Equal(t, `illegal argument: part "15" of identifier "1_2_15" isn't a number of 3 digits`, err.Error())
+	_, err = csWith1DigitAppCodeAndModuleCode.Parse("01_2_105")
+	assert.Equal(t, `illegal argument: part "01" of identifier "01_2_105" isn't a number of 1 digits`, err.Error())
+	_, err = csWith1DigitAppCodeAndModuleCode.Parse("1_x_105")
+	assert.NotNil(t, err)
+	_, err = csWithoutAppCodeAndModuleCode.Parse("+01")
+	assert.NotNil(t, err)
+	_, err = csWithoutAppCodeAndModuleCode.Parse("")
+	assert.NotNil(t, err)
+
+	_, err = csWith1DigitAppCodeAndModuleCode.Parse("1_2_000")
+	assert.Equal(t, `illegal argument: case code 0 of identifier "1_2_000" isn't in any CaseCodeSegment`, err.Error())
+	_, err = csWith1DigitAppCodeAndModuleCode.Parse("1_2_999")
+	assert.NotNil(t, err)
+}
+
+// Tests for CodingStrategy end
+
 // Tests for CodingStrategyBuilder start
 
 func TestNewCodingStrategyBuilder(t *testing.T) {
@@ -513,9 +656,74 @@ func TestCodingStrategyBuilder_Build_CaseCodeRangeNotIncludeCaseCodeSegmentDefin
 	b.NumDigitsOfCaseCode(2)
 	b.StatusCodeMapper(NewCodeMapper(&DefaultCodeMapper{}))
 	_, err := b.Build()
-	assert.Equal(t,
-		"illegal argument: case code range [0, 99] of CodingStrategy doesn't include caseCodeSegment [51, 100] defined by CodeMapper",
-		err.Error())
+	verr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Len(t, verr.Problems, 10)
+	assert.Equal(t, "segment [51, 100] for DeadlineExceeded isn't inside case code range [0, 99]", verr.Problems[0])
+	assert.Equal(t, "segment [501, 550] for DataLoss isn't inside case code range [0, 99]", verr.Problems[9])
+}
+
+func TestCodingStrategyBuilder_Build_ReportsAllProblems(t *testing.T) {
+	m := NewCodeMapper(&overlappingOutOfRangeCodeMapper{})
+	_, err := NewCodingStrategyBuilder().NumDigitsOfCaseCode(2).StatusCodeMapper(m).Build()
+	assert.Equal(t, &ValidationError{Problems: []string{
+		"segment [90, 120] for NotFound isn't inside case code range [0, 99]",
+		"segment [1, 50] for InvalidArgument overlaps segment [40, 60] for DeadlineExceeded in [40, 50]",
+	}}, err)
+}
+
+type overlappingOutOfRangeCodeMapper struct {
+	CodeMapperBase
+}
+
+func (m *overlappingOutOfRangeCodeMapper) InvalidArgument() *CaseCodeSegment {
+	r, _ := NewNumRange(1, 50)
+	return r
+}
+
+func (m *overlappingOutOfRangeCodeMapper) DeadlineExceeded() *CaseCodeSegment {
+	r, _ := NewNumRange(40, 60)
+	return r
+}
+
+func (m *overlappingOutOfRangeCodeMapper) NotFound() *CaseCodeSegment {
+	r, _ := NewNumRange(90, 120)
+	return r
 }
 
 // Tests for CodingStrategyBuilder end
+
+func TestCaseFactory_Lifecycle(t *testing.T) {
+	f, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2))
+	notFound, err := f.NewNotFound(101, WithSince("v1.2.0"))
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.Lifecycle{Since: "v1.2.0", Visibility: domainerr.VisibilityPublic}, notFound.Lifecycle())
+
+	deprecated, err := f.NewNotFound(102, WithDeprecated(notFound), WithVisibility(domainerr.VisibilityInternal))
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.Lifecycle{
+		Deprecated: true, Replacement: notFound, Visibility: domainerr.VisibilityInternal,
+	}, deprecated.Lifecycle())
+	assert.Equal(t, "case 1_2_102 is deprecated, use 1_2_101 instead", domainerr.DeprecationNotice(deprecated))
+
+	_, err = f.NewNotFound(103, WithSince(""))
+	assert.Equal(t, "illegal argument: version is blank", err.Error())
+	_, err = f.NewNotFound(103, WithVisibility("secret"))
+	assert.Equal(t, `illegal argument: unknown visibility "secret"`, err.Error())
+	_, err = f.NewNotFound(101, WithDeprecated(notFound))
+	assert.Equal(t, "illegal argument: case 1_2_101 is replaced by itself", err.Error())
+}
+
+func TestCaseFactory_FaultAndSeverity(t *testing.T) {
+	f, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2))
+	c, err := f.NewFailedPrecondition(301, WithFault(domainerr.DependencyFault), WithSeverity(domainerr.SeverityError))
+	assert.Nil(t, err)
+	s := domainerr.NewWithCode(c.StatusCode()).WithCase(c)
+	assert.Equal(t, domainerr.DependencyFault, s.Fault())
+	assert.Equal(t, domainerr.SeverityError, s.Severity())
+
+	_, err = f.NewFailedPrecondition(302, WithFault("nobody"))
+	assert.Equal(t, `illegal argument: illegal fault "nobody"`, err.Error())
+	_, err = f.NewFailedPrecondition(302, WithSeverity(domainerr.SeverityCritical+1))
+	assert.Equal(t, "illegal argument: illegal severity 5", err.Error())
+}
@@ -0,0 +1,152 @@
+package numcase
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// Owner describes the team owning an app or a module.
+type Owner struct {
+	Team        string `json:"team" yaml:"team"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type moduleKey struct {
+	appCode    int
+	moduleCode int
+}
+
+type caseKey struct {
+	moduleKey
+	caseCode int
+}
+
+// Catalog records which team owns which app and module, and the cases created by the factories of
+// a service, so that claims of the same app, module or case code are detected. Export the Manifest
+// of each service and merge them with MergeManifests to detect collisions across services.
+//
+//	catalog := numcase.NewCatalog("order-service")
+//	_ = catalog.ClaimApp(1, numcase.Owner{Team: "trade"})
+//	_ = catalog.ClaimModule(1, 2, numcase.Owner{Team: "trade-order"})
+//	f, err := numcase.NewFactory(strategy, numcase.WithAppCode(1), numcase.WithModuleCode(2),
+//		numcase.WithCatalog(catalog))
+//
+// It is safe for concurrent use.
+type Catalog struct {
+	service string
+
+	mu      sync.Mutex
+	apps    map[int]Owner
+	modules map[moduleKey]Owner
+	cases   map[caseKey]*NumCase
+}
+
+// NewCatalog creates a Catalog of the given service, whose name is recorded in the manifest.
+func NewCatalog(service string) *Catalog {
+	return &Catalog{
+		service: service,
+		apps:    make(map[int]Owner),
+		modules: make(map[moduleKey]Owner),
+		cases:   make(map[caseKey]*NumCase),
+	}
+}
+
+// ClaimApp records that the given app is owned by owner. It returns an error if the app is
+// already claimed by another owner.
+func (c *Catalog) ClaimApp(appCode int, owner Owner) error {
+	err := domainerr.CheckArgument(owner.Team != "", "owner.Team is empty")
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if claimed, found := c.apps[appCode]; found {
+		return domainerr.CheckArgument(claimed == owner,
+			"app %d is already claimed by team %s", appCode, claimed.Team)
+	}
+	c.apps[appCode] = owner
+	return nil
+}
+
+// ClaimModule records that the given module of the given app is owned by owner. It returns an
+// error if the module is already claimed by another owner.
+func (c *Catalog) ClaimModule(appCode int, moduleCode int, owner Owner) error {
+	err := domainerr.CheckArgument(owner.Team != "", "owner.Team is empty")
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := moduleKey{appCode: appCode, moduleCode: moduleCode}
+	if claimed, found := c.modules[key]; found {
+		return domainerr.CheckArgument(claimed == owner,
+			"module %d of app %d is already claimed by team %s", moduleCode, appCode, claimed.Team)
+	}
+	c.modules[key] = owner
+	return nil
+}
+
+// checkClaimed checks that the app and the module of the given factory are claimed, if the coding
+// strategy has such codes.
+func (c *Catalog) checkClaimed(f *CaseFactory) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if f.codingStrategy.numDigitsOfAppCode > 0 {
+		_, found := c.apps[f.appCode]
+		err := domainerr.CheckArgument(found, "app %d isn't claimed in the catalog", f.appCode)
+		if err != nil {
+			return err
+		}
+	}
+	if f.codingStrategy.numDigitsOfModuleCode > 0 {
+		_, found := c.modules[moduleKey{appCode: f.appCode, moduleCode: f.moduleCode}]
+		err := domainerr.CheckArgument(found,
+			"module %d of app %d isn't claimed in the catalog", f.moduleCode, f.appCode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register records the given case. It returns an error if the case code is already used in the
+// module.
+func (c *Catalog) register(nc *NumCase) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := caseKey{moduleKey: moduleKey{appCode: nc.appCode, moduleCode: nc.moduleCode}, caseCode: nc.caseCode}
+	if registered, found := c.cases[key]; found {
+		return domainerr.CheckArgument(false, "case code %d is already used by case %s",
+			nc.caseCode, registered.Identifier())
+	}
+	c.cases[key] = nc
+	return nil
+}
+
+// Manifest exports the apps, the modules and the cases recorded in this catalog.
+func (c *Catalog) Manifest() *Manifest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := &Manifest{}
+	for appCode, owner := range c.apps {
+		m.Apps = append(m.Apps, AppEntry{Service: c.service, AppCode: appCode, Owner: owner})
+	}
+	for key, owner := range c.modules {
+		m.Modules = append(m.Modules, ModuleEntry{
+			Service: c.service, AppCode: key.appCode, ModuleCode: key.moduleCode, Owner: owner,
+		})
+	}
+	for _, nc := range c.cases {
+		m.Cases = append(m.Cases, caseEntryOf(c.service, nc))
+	}
+	m.sort()
+	return m
+}
+
+func (k caseKey) String() string {
+	return fmt.Sprintf("app %d module %d case %d", k.appCode, k.moduleCode, k.caseCode)
+}
@@ -0,0 +1,85 @@
+package numcase
+
+import (
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog_Claim(t *testing.T) {
+	c := NewCatalog("order-service")
+	trade := Owner{Team: "trade", Description: "trading"}
+	assert.Nil(t, c.ClaimApp(1, trade))
+	assert.Nil(t, c.ClaimApp(1, trade))
+	err := c.ClaimApp(1, Owner{Team: "payment"})
+	assert.Equal(t, "illegal argument: app 1 is already claimed by team trade", err.Error())
+	err = c.ClaimApp(2, Owner{})
+	assert.Equal(t, "illegal argument: owner.Team is empty", err.Error())
+
+	assert.Nil(t, c.ClaimModule(1, 2, trade))
+	assert.Nil(t, c.ClaimModule(1, 3, Owner{Team: "trade-cart"}))
+	err = c.ClaimModule(1, 2, Owner{Team: "trade-cart"})
+	assert.Equal(t, "illegal argument: module 2 of app 1 is already claimed by team trade", err.Error())
+}
+
+func TestCatalog_Factory(t *testing.T) {
+	c := NewCatalog("order-service")
+	_, err := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2), WithCatalog(c))
+	assert.Equal(t, "illegal argument: app 1 isn't claimed in the catalog", err.Error())
+	_ = c.ClaimApp(1, Owner{Team: "trade"})
+	_, err = NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2), WithCatalog(c))
+	assert.Equal(t, "illegal argument: module 2 of app 1 isn't claimed in the catalog", err.Error())
+	_ = c.ClaimModule(1, 2, Owner{Team: "trade-order"})
+	f, err := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2), WithCatalog(c))
+	assert.Nil(t, err)
+	_, err = NewFactory(csWith1DigitAppCodeAndModuleCode, WithCatalog(nil))
+	assert.Equal(t, "illegal argument: catalog is nil", err.Error())
+
+	_, err = f.NewNotFound(101)
+	assert.Nil(t, err)
+	_, err = f.NewNotFound(101)
+	assert.Equal(t, "illegal argument: case code 101 is already used by case 1_2_101", err.Error())
+
+	// another factory of the same module shares the case codes
+	f2, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2), WithCatalog(c))
+	_, err = f2.NewNotFound(101)
+	assert.NotNil(t, err)
+	_, err = f2.NewInvalidArgument(1)
+	assert.Nil(t, err)
+
+	// no app code and module code to claim
+	f3, err := NewFactory(csWithoutAppCodeAndModuleCode, WithCatalog(NewCatalog("order-service")))
+	assert.Nil(t, err)
+	_, err = f3.NewNotFound(101)
+	assert.Nil(t, err)
+}
+
+func TestCatalog_Manifest(t *testing.T) {
+	c := NewCatalog("order-service")
+	_ = c.ClaimApp(1, Owner{Team: "trade"})
+	_ = c.ClaimModule(1, 3, Owner{Team: "trade-cart"})
+	_ = c.ClaimModule(1, 2, Owner{Team: "trade-order", Description: "orders"})
+	f, _ := NewFactory(csWith1DigitAppCodeAndModuleCode, WithAppCode(1), WithModuleCode(2), WithCatalog(c))
+	notFound, _ := f.NewNotFound(101, WithSince("v1.2.0"))
+	_, _ = f.NewNotFound(102, WithDeprecated(notFound), WithVisibility(domainerr.VisibilityInternal))
+	_, _ = f.NewInvalidArgument(1)
+
+	assert.Equal(t, &Manifest{
+		Apps: []AppEntry{{Service: "order-service", AppCode: 1, Owner: Owner{Team: "trade"}}},
+		Modules: []ModuleEntry{
+			{Service: "order-service", AppCode: 1, ModuleCode: 2, Owner: Owner{Team: "trade-order", Description: "orders"}},
+			{Service: "order-service", AppCode: 1, ModuleCode: 3, Owner: Owner{Team: "trade-cart"}},
+		},
+		Cases: []CaseEntry{
+			{Service: "order-service", Identifier: "1_2_001", AppCode: 1, ModuleCode: 2, CaseCode: 1, StatusCode: "InvalidArgument",
+				HTTPStatus: 400, RetryAdvice: domainerr.NoAdvice, Visibility: domainerr.VisibilityPublic},
+			{Service: "order-service", Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "NotFound",
+				HTTPStatus: 404, RetryAdvice: domainerr.NoAdvice, Since: "v1.2.0", Visibility: domainerr.VisibilityPublic},
+			{Service: "order-service", Identifier: "1_2_102", AppCode: 1, ModuleCode: 2, CaseCode: 102, StatusCode: "NotFound",
+				HTTPStatus: 404, RetryAdvice: domainerr.NoAdvice, Deprecated: true, Replacement: "1_2_101",
+				Visibility: domainerr.VisibilityInternal},
+		},
+	}, c.Manifest())
+}
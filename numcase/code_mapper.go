@@ -2,7 +2,7 @@ package numcase
 
 import (
 	"fmt"
-	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/ikonglong/domainerr"
@@ -43,26 +43,66 @@ type CodeMapper interface {
 	InternalError() *CaseCodeSegment
 	// DataLoss declares a mapping from CodeDataLoss to the returned CaseCodeSegment.
 	DataLoss() *CaseCodeSegment
+
+	// The methods below declare optional mappings, which a concrete mapper embedding CodeMapperBase
+	// only overrides if it needs them.
+
+	// Cancelled declares a mapping from CodeCancelled to the returned CaseCodeSegment, if not nil.
+	Cancelled() *CaseCodeSegment
+	// Unknown declares a mapping from CodeUnknown to the returned CaseCodeSegment, if not nil.
+	Unknown() *CaseCodeSegment
+	// Unauthenticated declares a mapping from CodeUnauthenticated to the returned CaseCodeSegment,
+	// if not nil.
+	Unauthenticated() *CaseCodeSegment
+	// Unimplemented declares a mapping from CodeUnimplemented to the returned CaseCodeSegment, if
+	// not nil.
+	Unimplemented() *CaseCodeSegment
+	// Unavailable declares a mapping from CodeUnavailable to the returned CaseCodeSegment, if not
+	// nil.
+	Unavailable() *CaseCodeSegment
+	// Undefined declares a mapping from CodeUndefined to the returned CaseCodeSegment, if not nil.
+	Undefined() *CaseCodeSegment
+	// AuthorizationExpired declares a mapping from CodeAuthorizationExpired to the returned
+	// CaseCodeSegment, if not nil.
+	AuthorizationExpired() *CaseCodeSegment
 }
 
+// CodeMapperBase implements all the declaring methods of CodeMapper by looking up its mappings, so
+// they return nil, i.e., no mapping, unless a concrete mapper embedding it overrides them.
 type CodeMapperBase struct {
 	CodeMapper
 	statusCodeToCaseCodeSeg map[domainerr.Code]*CaseCodeSegment
 }
 
+// NewCodeMapper creates a CodeMapper from the mappings declared by concreteMapper. A declaring
+// method returning nil means there is no mapping for the status code.
 func NewCodeMapper(concreteMapper CodeMapper) CodeMapper {
-	mappings := make(map[domainerr.Code]*CaseCodeSegment, len(domainerr.CodeList))
-	mappings[domainerr.CodeInvalidArgument] = concreteMapper.InvalidArgument()
-	mappings[domainerr.CodeDeadlineExceeded] = concreteMapper.DeadlineExceeded()
-	mappings[domainerr.CodeNotFound] = concreteMapper.NotFound()
-	mappings[domainerr.CodeAlreadyExists] = concreteMapper.AlreadyExists()
-	mappings[domainerr.CodePermissionDenied] = concreteMapper.PermissionDenied()
-	mappings[domainerr.CodeResourceExhausted] = concreteMapper.ResourceExhausted()
-	mappings[domainerr.CodeFailedPrecondition] = concreteMapper.FailedPrecondition()
-	mappings[domainerr.CodeAborted] = concreteMapper.Aborted()
-	mappings[domainerr.CodeOutOfRange] = concreteMapper.OutOfRange()
-	mappings[domainerr.CodeInternalError] = concreteMapper.InternalError()
-	mappings[domainerr.CodeDataLoss] = concreteMapper.DataLoss()
+	declared := map[domainerr.Code]*CaseCodeSegment{
+		domainerr.CodeCancelled:            concreteMapper.Cancelled(),
+		domainerr.CodeUnknown:              concreteMapper.Unknown(),
+		domainerr.CodeInvalidArgument:      concreteMapper.InvalidArgument(),
+		domainerr.CodeDeadlineExceeded:     concreteMapper.DeadlineExceeded(),
+		domainerr.CodeNotFound:             concreteMapper.NotFound(),
+		domainerr.CodeAlreadyExists:        concreteMapper.AlreadyExists(),
+		domainerr.CodePermissionDenied:     concreteMapper.PermissionDenied(),
+		domainerr.CodeUnauthenticated:      concreteMapper.Unauthenticated(),
+		domainerr.CodeResourceExhausted:    concreteMapper.ResourceExhausted(),
+		domainerr.CodeFailedPrecondition:   concreteMapper.FailedPrecondition(),
+		domainerr.CodeAborted:              concreteMapper.Aborted(),
+		domainerr.CodeOutOfRange:           concreteMapper.OutOfRange(),
+		domainerr.CodeUnimplemented:        concreteMapper.Unimplemented(),
+		domainerr.CodeInternalError:        concreteMapper.InternalError(),
+		domainerr.CodeUnavailable:          concreteMapper.Unavailable(),
+		domainerr.CodeDataLoss:             concreteMapper.DataLoss(),
+		domainerr.CodeUndefined:            concreteMapper.Undefined(),
+		domainerr.CodeAuthorizationExpired: concreteMapper.AuthorizationExpired(),
+	}
+	mappings := make(map[domainerr.Code]*CaseCodeSegment, len(declared))
+	for statusCode, segment := range declared {
+		if segment != nil {
+			mappings[statusCode] = segment
+		}
+	}
 	return &CodeMapperBase{
 		CodeMapper:              concreteMapper,
 		statusCodeToCaseCodeSeg: mappings,
@@ -94,6 +134,106 @@ func (m *CodeMapperBase) Mappings() map[domainerr.Code]*CaseCodeSegment {
 	return _copy
 }
 
+// InvalidArgument returns the CaseCodeSegment mapped to CodeInvalidArgument, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) InvalidArgument() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeInvalidArgument]
+}
+
+// DeadlineExceeded returns the CaseCodeSegment mapped to CodeDeadlineExceeded, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) DeadlineExceeded() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeDeadlineExceeded]
+}
+
+// NotFound returns the CaseCodeSegment mapped to CodeNotFound, or nil if there is no mapping.
+func (m *CodeMapperBase) NotFound() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeNotFound]
+}
+
+// AlreadyExists returns the CaseCodeSegment mapped to CodeAlreadyExists, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) AlreadyExists() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeAlreadyExists]
+}
+
+// PermissionDenied returns the CaseCodeSegment mapped to CodePermissionDenied, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) PermissionDenied() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodePermissionDenied]
+}
+
+// ResourceExhausted returns the CaseCodeSegment mapped to CodeResourceExhausted, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) ResourceExhausted() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeResourceExhausted]
+}
+
+// FailedPrecondition returns the CaseCodeSegment mapped to CodeFailedPrecondition, or nil if there
+// is no mapping.
+func (m *CodeMapperBase) FailedPrecondition() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeFailedPrecondition]
+}
+
+// Aborted returns the CaseCodeSegment mapped to CodeAborted, or nil if there is no mapping.
+func (m *CodeMapperBase) Aborted() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeAborted]
+}
+
+// OutOfRange returns the CaseCodeSegment mapped to CodeOutOfRange, or nil if there is no mapping.
+func (m *CodeMapperBase) OutOfRange() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeOutOfRange]
+}
+
+// InternalError returns the CaseCodeSegment mapped to CodeInternalError, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) InternalError() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeInternalError]
+}
+
+// DataLoss returns the CaseCodeSegment mapped to CodeDataLoss, or nil if there is no mapping.
+func (m *CodeMapperBase) DataLoss() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeDataLoss]
+}
+
+// Cancelled returns the CaseCodeSegment mapped to CodeCancelled, or nil if there is no mapping.
+func (m *CodeMapperBase) Cancelled() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeCancelled]
+}
+
+// Unknown returns the CaseCodeSegment mapped to CodeUnknown, or nil if there is no mapping.
+func (m *CodeMapperBase) Unknown() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeUnknown]
+}
+
+// Unauthenticated returns the CaseCodeSegment mapped to CodeUnauthenticated, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) Unauthenticated() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeUnauthenticated]
+}
+
+// Unimplemented returns the CaseCodeSegment mapped to CodeUnimplemented, or nil if there is
+// no mapping.
+func (m *CodeMapperBase) Unimplemented() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeUnimplemented]
+}
+
+// Unavailable returns the CaseCodeSegment mapped to CodeUnavailable, or nil if there is no mapping.
+func (m *CodeMapperBase) Unavailable() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeUnavailable]
+}
+
+// Undefined returns the CaseCodeSegment mapped to CodeUndefined, or nil if there is no mapping.
+func (m *CodeMapperBase) Undefined() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeUndefined]
+}
+
+// AuthorizationExpired returns the CaseCodeSegment mapped to CodeAuthorizationExpired, or nil if
+// there is no mapping.
+func (m *CodeMapperBase) AuthorizationExpired() *CaseCodeSegment {
+	return m.statusCodeToCaseCodeSeg[domainerr.CodeAuthorizationExpired]
+}
+
 func (m *CodeMapperBase) String() string {
 	// invert the map, and sort the keys
 	segmentToOpStatusCode := make(map[*CaseCodeSegment]domainerr.Code, len(m.statusCodeToCaseCodeSeg))
@@ -102,9 +242,7 @@ func (m *CodeMapperBase) String() string {
 		segmentToOpStatusCode[segment] = statusCode
 		segments = append(segments, segment)
 	}
-	sort.Slice(segments, func(i, j int) bool {
-		return segments[i].End() <= segments[j].Start()
-	})
+	sortSegments(segments)
 
 	// build the string
 	sb := strings.Builder{}
@@ -113,9 +251,12 @@ func (m *CodeMapperBase) String() string {
 		"Case Code Segment", "Operation Status (Name:Code)", "HTTP Status (Name:Code)"))
 	for _, segment := range segments {
 		opStatusCode := segmentToOpStatusCode[segment]
+		httpStatusName, httpStatusCode := "-", "-"
+		if httpStatus := opStatusCode.ToHTTPStatus(); httpStatus != nil {
+			httpStatusName, httpStatusCode = httpStatus.Name(), strconv.Itoa(httpStatus.Code())
+		}
 		sb.WriteString(fmt.Sprintf("| %-20s | %-20s:%-10v | %-20s:%-10v |\n",
-			segment, opStatusCode.Name(), opStatusCode.Value(),
-			opStatusCode.ToHTTPStatus().Name(), opStatusCode.ToHTTPStatus().Code()))
+			segment, opStatusCode.Name(), opStatusCode.Value(), httpStatusName, httpStatusCode))
 	}
 	return sb.String()
 }
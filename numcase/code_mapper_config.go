@@ -0,0 +1,298 @@
+package numcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ikonglong/domainerr"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError reports all the problems found when validating a CodeMapper or its config.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid CaseCodeSegments: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ValidationError) addf(format string, args ...any) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// orNil returns e if it has any problem, or nil otherwise.
+func (e *ValidationError) orNil() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+type CodeMapperOpt func(o *codeMapperOptions)
+
+type codeMapperOptions struct {
+	numDigitsOfCaseCode int
+	radix               int
+}
+
+// WithinDigitsOfCaseCode requires all the segments to fall inside the case code range of a
+// CodingStrategy with the given number of digits of the case code in the given radix, which is
+// the one of its IdentifierFormat, e.g., [0, 999] for 3 digits in radix 10, or [0, 255] for 2
+// digits in radix 16.
+func WithinDigitsOfCaseCode(numDigits int, radix int) CodeMapperOpt {
+	return func(o *codeMapperOptions) {
+		o.numDigitsOfCaseCode = numDigits
+		o.radix = radix
+	}
+}
+
+// caseCodeRange returns the case code range required by WithinDigitsOfCaseCode, or nil if it isn't
+// required. Problems of the options are added to verr.
+func (o *codeMapperOptions) caseCodeRange(verr *ValidationError) *NumRange {
+	if o.radix == 0 {
+		return nil
+	}
+	if o.radix < 2 || o.radix > 36 {
+		verr.addf("radix %d not in [2, 36]", o.radix)
+		return nil
+	}
+	if o.numDigitsOfCaseCode < 0 {
+		verr.addf("numDigitsOfCaseCode < 0")
+		return nil
+	}
+	size := intPow(o.radix, o.numDigitsOfCaseCode)
+	if size <= 0 || size-1 > int64(maxInt) {
+		verr.addf("%d digits in radix %d overflow int", o.numDigitsOfCaseCode, o.radix)
+		return nil
+	}
+	return &NumRange{start: 0, end: int(size - 1)}
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// NewCodeMapperFromSegments creates a CodeMapper from the given mappings, which is an alternative
+// to implementing a concrete CodeMapper. E.g.,
+//
+//	invalidArg, _ := numcase.NewNumRange(1, 100)
+//	notFound, _ := numcase.NewNumRange(101, 200)
+//	m, err := numcase.NewCodeMapperFromSegments(map[domainerr.Code]numcase.NumRange{
+//		domainerr.CodeInvalidArgument: *invalidArg,
+//		domainerr.CodeNotFound:        *notFound,
+//	})
+//
+// It validates that the status codes are well-defined and not CodeOK, the segments are not empty,
+// don't overlap with each other, and fall inside the case code range if required by
+// WithinDigitsOfCaseCode. All the problems found are reported together in a *ValidationError.
+func NewCodeMapperFromSegments(segments map[domainerr.Code]NumRange, opts ...CodeMapperOpt) (CodeMapper, error) {
+	var o codeMapperOptions
+	for _, setOpt := range opts {
+		setOpt(&o)
+	}
+
+	verr := &ValidationError{}
+	caseCodeRange := o.caseCodeRange(verr)
+	mappings := make(map[domainerr.Code]*CaseCodeSegment, len(segments))
+	for _, statusCode := range sortedCodes(segments) {
+		seg := segments[statusCode]
+		switch {
+		case !isWellDefined(statusCode):
+			verr.addf("status code %s isn't well-defined", statusCode.String())
+		case statusCode == domainerr.CodeOK:
+			verr.addf("status code %s can't be mapped", statusCode.String())
+		case seg.end < seg.start:
+			verr.addf("segment for %s is empty: end < start", statusCode.Name())
+		case caseCodeRange != nil && !caseCodeRange.includeRange(&seg):
+			verr.addf("segment %s for %s isn't inside case code range %s",
+				seg.String(), statusCode.Name(), caseCodeRange.String())
+		default:
+			seg := seg
+			mappings[statusCode] = &seg
+		}
+	}
+
+	overlaps, _ := checkSegments(mappings)
+	verr.Problems = append(verr.Problems, overlaps...)
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+	return &CodeMapperBase{statusCodeToCaseCodeSeg: mappings}, nil
+}
+
+// CodeMapperConfig is the config of a CodeMapper, which can be loaded from JSON or YAML, e.g.,
+//
+//	numDigitsOfCaseCode: 3
+//	radix: 10
+//	segments:
+//	  InvalidArgument: [1, 100]
+//	  NotFound: [101, 200]
+//
+// The status codes are referred to by their names, i.e., Code.Name().
+type CodeMapperConfig struct {
+	// NumDigitsOfCaseCode requires all the segments to fall inside the case code range if > 0.
+	NumDigitsOfCaseCode int `json:"numDigitsOfCaseCode" yaml:"numDigitsOfCaseCode"`
+	// Radix is the radix of the case code range, which is 10 if it's 0.
+	Radix    int               `json:"radix" yaml:"radix"`
+	Segments map[string][2]int `json:"segments" yaml:"segments"`
+}
+
+// Build validates this config and creates the CodeMapper. All the problems found are reported
+// together in a *ValidationError.
+func (c *CodeMapperConfig) Build() (CodeMapper, error) {
+	verr := &ValidationError{}
+	segments := make(map[domainerr.Code]NumRange, len(c.Segments))
+	names := make([]string, 0, len(c.Segments))
+	for name := range c.Segments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		bounds := c.Segments[name]
+		statusCode, found := domainerr.CodeByName(name)
+		if !found {
+			verr.addf("unknown status code %q", name)
+			continue
+		}
+		seg, err := NewNumRange(bounds[0], bounds[1])
+		if err != nil {
+			verr.addf("segment %v for %s is illegal: %v", bounds, name, err)
+			continue
+		}
+		segments[statusCode] = *seg
+	}
+
+	var opts []CodeMapperOpt
+	if c.NumDigitsOfCaseCode > 0 {
+		radix := c.Radix
+		if radix == 0 {
+			radix = 10
+		}
+		opts = append(opts, WithinDigitsOfCaseCode(c.NumDigitsOfCaseCode, radix))
+	}
+	m, err := NewCodeMapperFromSegments(segments, opts...)
+	if err != nil {
+		if e, ok := err.(*ValidationError); ok {
+			verr.Problems = append(verr.Problems, e.Problems...)
+		}
+	}
+	if err = verr.orNil(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadCodeMapperJSON creates a CodeMapper from a CodeMapperConfig in JSON.
+func LoadCodeMapperJSON(data []byte) (CodeMapper, error) {
+	var c CodeMapperConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode CodeMapperConfig: %w", err)
+	}
+	return c.Build()
+}
+
+// LoadCodeMapperYAML creates a CodeMapper from a CodeMapperConfig in YAML.
+func LoadCodeMapperYAML(data []byte) (CodeMapper, error) {
+	var c CodeMapperConfig
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode CodeMapperConfig: %w", err)
+	}
+	return c.Build()
+}
+
+// AutoLayout splits the given case code space into consecutive segments for the given status codes
+// in order, see NumRange.Split. The whole space is used: the first segments are one longer than
+// the others if the space can't be split evenly. E.g., splitting [1, 998] for 3 codes gives
+// [1, 333], [334, 666] and [667, 998].
+//
+// The result can be passed to NewCodeMapperFromSegments.
+func AutoLayout(space NumRange, codes ...domainerr.Code) (map[domainerr.Code]NumRange, error) {
+	err := domainerr.CheckArgument(len(codes) > 0, "no status codes")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(space.Len() >= len(codes),
+		"space %s is too small for %d status codes", space.String(), len(codes))
+	if err != nil {
+		return nil, err
+	}
+	parts, err := space.Split(len(codes))
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make(map[domainerr.Code]NumRange, len(codes))
+	for i, statusCode := range codes {
+		_, found := segments[statusCode]
+		err = domainerr.CheckArgument(!found, "duplicate status code %s", statusCode.String())
+		if err != nil {
+			return nil, err
+		}
+		segments[statusCode] = *parts[i]
+	}
+	return segments, nil
+}
+
+// checkSegments checks the given mappings, and returns the overlaps between the segments, and the
+// gaps between the consecutive segments, which are numbers not mapped to any status code.
+func checkSegments(mappings map[domainerr.Code]*CaseCodeSegment) (overlaps []string, gaps []string) {
+	codes := sortedCodes(mappings)
+	for i, c1 := range codes {
+		for _, c2 := range codes[i+1:] {
+			s1, s2 := mappings[c1], mappings[c2]
+			if s1.Overlaps(s2) {
+				overlaps = append(overlaps, fmt.Sprintf("segment %s for %s overlaps segment %s for %s in %s",
+					s1.String(), c1.Name(), s2.String(), c2.Name(), s1.Intersect(s2).String()))
+			}
+		}
+	}
+
+	segs := make([]*CaseCodeSegment, 0, len(mappings))
+	segToCode := make(map[*CaseCodeSegment]domainerr.Code, len(mappings))
+	for _, c := range codes {
+		segs = append(segs, mappings[c])
+		segToCode[mappings[c]] = c
+	}
+	sortSegments(segs)
+	for i := 1; i < len(segs); i++ {
+		prev, next := segs[i-1], segs[i]
+		if next.start > prev.end+1 {
+			prevCode, nextCode := segToCode[prev], segToCode[next]
+			gaps = append(gaps, fmt.Sprintf("case codes [%d, %d] between segment %s for %s and segment %s for %s are unmapped",
+				prev.end+1, next.start-1, prev.String(), prevCode.Name(), next.String(), nextCode.Name()))
+		}
+	}
+	return overlaps, gaps
+}
+
+// sortSegments sorts the given segments by their starts, and then their ends.
+func sortSegments(segs []*CaseCodeSegment) {
+	sort.Slice(segs, func(i, j int) bool {
+		if segs[i].start != segs[j].start {
+			return segs[i].start < segs[j].start
+		}
+		return segs[i].end < segs[j].end
+	})
+}
+
+func isWellDefined(statusCode domainerr.Code) bool {
+	for _, c := range domainerr.CodeList {
+		if c == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedCodes returns the keys of the given map sorted by the values of the codes.
+func sortedCodes[V any](m map[domainerr.Code]V) []domainerr.Code {
+	codes := make([]domainerr.Code, 0, len(m))
+	for c := range m {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].Value() < codes[j].Value()
+	})
+	return codes
+}
@@ -0,0 +1,149 @@
+package numcase
+
+import (
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func numRange(start int, end int) NumRange {
+	r, _ := NewNumRange(start, end)
+	return *r
+}
+
+func TestNewCodeMapperFromSegments(t *testing.T) {
+	m, err := NewCodeMapperFromSegments(map[domainerr.Code]NumRange{
+		domainerr.CodeInvalidArgument: numRange(1, 100),
+		domainerr.CodeNotFound:        numRange(101, 200),
+		domainerr.CodeUnavailable:     numRange(201, 300),
+	}, WithinDigitsOfCaseCode(3, 10))
+	assert.Nil(t, err)
+	assert.Len(t, m.Mappings(), 3)
+	assert.Equal(t, "[1, 100]", m.InvalidArgument().String())
+	assert.Equal(t, "[101, 200]", m.CaseCodeSegmentFor(domainerr.CodeNotFound).String())
+	assert.Equal(t, "[201, 300]", m.Unavailable().String())
+	assert.False(t, m.HasMappingFor(domainerr.CodeDataLoss))
+	assert.Nil(t, m.DataLoss())
+
+	strategy, err := NewCodingStrategyBuilder().NumDigitsOfCaseCode(3).StatusCodeMapper(m).Build()
+	assert.Nil(t, err)
+	c, err := strategy.Parse("250")
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.CodeUnavailable, c.StatusCode())
+}
+
+func TestNewCodeMapperFromSegments_ReportsAllProblems(t *testing.T) {
+	_, err := NewCodeMapperFromSegments(map[domainerr.Code]NumRange{
+		domainerr.CodeOK:               numRange(1, 10),
+		domainerr.CodeInvalidArgument:  numRange(1, 100),
+		domainerr.CodeNotFound:         numRange(50, 150),
+		domainerr.CodeAlreadyExists:    numRange(90, 95),
+		domainerr.CodeDataLoss:         numRange(0, 0),
+		domainerr.CodeInternalError:    numRange(900, 1000),
+		domainerr.CodePermissionDenied: numRange(300, 400),
+	}, WithinDigitsOfCaseCode(3, 10))
+	verr, ok := err.(*ValidationError)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"status code OK(0) can't be mapped",
+		"segment [900, 1000] for InternalError isn't inside case code range [0, 999]",
+		"segment [1, 100] for InvalidArgument overlaps segment [50, 150] for NotFound in [50, 100]",
+		"segment [1, 100] for InvalidArgument overlaps segment [90, 95] for AlreadyExists in [90, 95]",
+		"segment [50, 150] for NotFound overlaps segment [90, 95] for AlreadyExists in [90, 95]",
+	}, verr.Problems)
+	assert.Contains(t, err.Error(), "invalid CaseCodeSegments: status code OK(0) can't be mapped; ")
+}
+
+func TestNewCodeMapperFromSegments_SingleCodeSegment(t *testing.T) {
+	m, err := NewCodeMapperFromSegments(map[domainerr.Code]NumRange{
+		domainerr.CodeInvalidArgument: {},
+		domainerr.CodeNotFound:        numRange(1, 1),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "[0, 0]", m.InvalidArgument().String())
+	assert.Equal(t, "[1, 1]", m.NotFound().String())
+}
+
+func TestNewCodeMapperFromSegments_Radix(t *testing.T) {
+	segments := map[domainerr.Code]NumRange{
+		domainerr.CodeInvalidArgument: numRange(1, 100),
+		domainerr.CodeNotFound:        numRange(101, 255),
+	}
+	_, err := NewCodeMapperFromSegments(segments, WithinDigitsOfCaseCode(2, 16))
+	assert.Nil(t, err)
+	_, err = NewCodeMapperFromSegments(segments, WithinDigitsOfCaseCode(2, 10))
+	assert.Equal(t, &ValidationError{Problems: []string{
+		"segment [1, 100] for InvalidArgument isn't inside case code range [0, 99]",
+		"segment [101, 255] for NotFound isn't inside case code range [0, 99]",
+	}}, err)
+	_, err = NewCodeMapperFromSegments(segments, WithinDigitsOfCaseCode(2, 37))
+	assert.Equal(t, &ValidationError{Problems: []string{"radix 37 not in [2, 36]"}}, err)
+}
+
+func TestLoadCodeMapperYAML(t *testing.T) {
+	m, err := LoadCodeMapperYAML([]byte(`
+numDigitsOfCaseCode: 3
+segments:
+  InvalidArgument: [1, 100]
+  NotFound: [101, 200]
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, "[1, 100]", m.InvalidArgument().String())
+	assert.Equal(t, "[101, 200]", m.NotFound().String())
+
+	_, err = LoadCodeMapperYAML([]byte(`
+numDigitsOfCaseCode: 2
+segments:
+  InvalidArgument: [1, 100]
+  NoSuchCode: [101, 200]
+  NotFound: [20, 10]
+`))
+	assert.Equal(t, &ValidationError{Problems: []string{
+		`unknown status code "NoSuchCode"`,
+		`segment [20 10] for NotFound is illegal: illegal argument: end < start`,
+		`segment [1, 100] for InvalidArgument isn't inside case code range [0, 99]`,
+	}}, err)
+
+	_, err = LoadCodeMapperYAML([]byte(`
+numDigitsOfCaseCode: 2
+radix: 16
+segments:
+  InvalidArgument: [1, 255]
+`))
+	assert.Nil(t, err)
+
+	_, err = LoadCodeMapperYAML([]byte(`segments: [1, 2]`))
+	assert.Contains(t, err.Error(), "failed to decode CodeMapperConfig")
+}
+
+func TestLoadCodeMapperJSON(t *testing.T) {
+	m, err := LoadCodeMapperJSON([]byte(`{"segments": {"Unauthenticated": [1, 10], "ServiceUnavailable": [11, 20]}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "[1, 10]", m.Unauthenticated().String())
+	assert.Equal(t, "[11, 20]", m.Unavailable().String())
+
+	_, err = LoadCodeMapperJSON([]byte(`{`))
+	assert.NotNil(t, err)
+}
+
+func TestAutoLayout(t *testing.T) {
+	segments, err := AutoLayout(numRange(1, 998),
+		domainerr.CodeInvalidArgument, domainerr.CodeNotFound, domainerr.CodeInternalError)
+	assert.Nil(t, err)
+	assert.Equal(t, map[domainerr.Code]NumRange{
+		domainerr.CodeInvalidArgument: numRange(1, 333),
+		domainerr.CodeNotFound:        numRange(334, 666),
+		domainerr.CodeInternalError:   numRange(667, 998),
+	}, segments)
+	_, err = NewCodeMapperFromSegments(segments)
+	assert.Nil(t, err)
+
+	_, err = AutoLayout(numRange(1, 2), domainerr.CodeInvalidArgument, domainerr.CodeNotFound, domainerr.CodeInternalError)
+	assert.Equal(t, "illegal argument: space [1, 2] is too small for 3 status codes", err.Error())
+	_, err = AutoLayout(numRange(1, 100))
+	assert.Equal(t, "illegal argument: no status codes", err.Error())
+	_, err = AutoLayout(numRange(1, 100), domainerr.CodeNotFound, domainerr.CodeNotFound)
+	assert.NotNil(t, err)
+}
@@ -1,6 +1,7 @@
 package numcase
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ikonglong/domainerr"
@@ -130,3 +131,59 @@ func TestDataLoss(t *testing.T) {
 	assert.Equal(t, r, defaultCodeMapper.DataLoss(),
 		"CaseCodeSegment for code %s should be %s", domainerr.CodeDataLoss.String(), r.String())
 }
+
+type mapperWithOptionalSegments struct {
+	DefaultCodeMapper
+}
+
+func (m *mapperWithOptionalSegments) Unavailable() *CaseCodeSegment {
+	r, _ := NewNumRange(551, 600)
+	return r
+}
+
+func (m *mapperWithOptionalSegments) Undefined() *CaseCodeSegment {
+	r, _ := NewNumRange(601, 650)
+	return r
+}
+
+func TestNewCodeMapper_OptionalSegments(t *testing.T) {
+	m := NewCodeMapper(&mapperWithOptionalSegments{})
+	unavailable, _ := NewNumRange(551, 600)
+	assert.True(t, m.HasMappingFor(domainerr.CodeUnavailable))
+	assert.Equal(t, unavailable, m.CaseCodeSegmentFor(domainerr.CodeUnavailable))
+	assert.Equal(t, unavailable, m.Unavailable())
+	assert.True(t, m.HasMappingFor(domainerr.CodeUndefined))
+	assert.Len(t, m.Mappings(), len(domainerr.CodeList)-numCodeNotNeedToMap()+2)
+	assert.Contains(t, m.(*CodeMapperBase).String(), "OperationNotDefined")
+
+	// not declared
+	assert.False(t, m.HasMappingFor(domainerr.CodeCancelled))
+	assert.Nil(t, m.CaseCodeSegmentFor(domainerr.CodeCancelled))
+	assert.Nil(t, m.Cancelled())
+}
+
+func TestDefaultCodeMapper_OptionalSegments(t *testing.T) {
+	for _, code := range codesNotNeedToMap {
+		assert.False(t, defaultCodeMapper.HasMappingFor(code), "%s should not be mapped", code.String())
+	}
+	assert.Nil(t, defaultCodeMapper.Unknown())
+	assert.Nil(t, defaultCodeMapper.Unauthenticated())
+	assert.Nil(t, defaultCodeMapper.Unimplemented())
+	assert.Nil(t, defaultCodeMapper.AuthorizationExpired())
+	for _, seg := range defaultCodeMapper.CaseCodeSegments() {
+		assert.NotNil(t, seg)
+	}
+}
+
+func TestCodeMapperBase_String_SortsSegments(t *testing.T) {
+	m, err := NewCodeMapperFromSegments(map[domainerr.Code]NumRange{
+		domainerr.CodeNotFound:        numRange(5, 5),
+		domainerr.CodeInvalidArgument: numRange(1, 1),
+		domainerr.CodeAlreadyExists:   numRange(3, 4),
+	})
+	assert.Nil(t, err)
+	lines := strings.Split(m.(*CodeMapperBase).String(), "\n")
+	assert.Contains(t, lines[2], "InvalidArgument")
+	assert.Contains(t, lines[3], "AlreadyExists")
+	assert.Contains(t, lines[4], "NotFound")
+}
@@ -0,0 +1,128 @@
+package numcase
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ChangeKind is the kind of a Change between two manifests.
+type ChangeKind string
+
+const (
+	// CaseRemoved means a case of the old manifest is absent in the new one.
+	CaseRemoved ChangeKind = "case_removed"
+	// StatusCodeChanged means the status code of a case is changed.
+	StatusCodeChanged ChangeKind = "status_code_changed"
+	// HTTPStatusChanged means the HTTP status of a case is changed.
+	HTTPStatusChanged ChangeKind = "http_status_changed"
+	// RetryAdviceChanged means the retry advice of a case is changed.
+	RetryAdviceChanged ChangeKind = "retry_advice_changed"
+	// IdentifierReused means an identifier of the old manifest identifies a case with other codes
+	// in the new one.
+	IdentifierReused ChangeKind = "identifier_reused"
+	// CaseCodeReused means the codes of a removed case are used by a case with another identifier.
+	CaseCodeReused ChangeKind = "case_code_reused"
+	// CaseAdded means a case of the new manifest is absent in the old one.
+	CaseAdded ChangeKind = "case_added"
+	// CaseDeprecated means a case is deprecated in the new manifest, which announces that it will
+	// be removed.
+	CaseDeprecated ChangeKind = "case_deprecated"
+)
+
+// Change is a difference of a case between two manifests.
+type Change struct {
+	Kind       ChangeKind `json:"kind"`
+	Identifier string     `json:"identifier"`
+	Old        string     `json:"old,omitempty"`
+	New        string     `json:"new,omitempty"`
+	// Breaking tells if the change breaks the clients relying on the old manifest.
+	Breaking bool `json:"breaking"`
+}
+
+func (c Change) String() string {
+	level := "info"
+	if c.Breaking {
+		level = "BREAKING"
+	}
+	switch {
+	case c.Old != "" && c.New != "":
+		return fmt.Sprintf("%s %s %s: %s -> %s", level, c.Kind, c.Identifier, c.Old, c.New)
+	case c.Old != "":
+		return fmt.Sprintf("%s %s %s: %s", level, c.Kind, c.Identifier, c.Old)
+	case c.New != "":
+		return fmt.Sprintf("%s %s %s: %s", level, c.Kind, c.Identifier, c.New)
+	default:
+		return fmt.Sprintf("%s %s %s", level, c.Kind, c.Identifier)
+	}
+}
+
+// CompatReport lists the changes of the cases from an old manifest to a new one.
+type CompatReport struct {
+	Changes []Change `json:"changes"`
+}
+
+// Breaking tells if any change is breaking.
+func (r *CompatReport) Breaking() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareManifests compares the cases of the old manifest and the new one. Since identifiers are
+// part of the public API, removing a case, changing its status code, HTTP status or retry advice,
+// and reusing an identifier or the codes of a removed case are breaking changes. Adding and
+// deprecating a case are not.
+func CompareManifests(oldM *Manifest, newM *Manifest) *CompatReport {
+	r := &CompatReport{}
+	newByID := make(map[string]CaseEntry, len(newM.Cases))
+	newByKey := make(map[caseKey]CaseEntry, len(newM.Cases))
+	for _, e := range newM.Cases {
+		newByID[e.Identifier] = e
+		newByKey[e.key()] = e
+	}
+
+	oldIDs := make(map[string]bool, len(oldM.Cases))
+	for _, o := range oldM.Cases {
+		oldIDs[o.Identifier] = true
+		n, found := newByID[o.Identifier]
+		if !found {
+			r.add(CaseRemoved, o.Identifier, o.StatusCode, "", true)
+			if reuser, reused := newByKey[o.key()]; reused {
+				r.add(CaseCodeReused, o.Identifier, o.key().String(), reuser.Identifier, true)
+			}
+			continue
+		}
+		if n.key() != o.key() {
+			r.add(IdentifierReused, o.Identifier, o.key().String(), n.key().String(), true)
+		}
+		if n.StatusCode != o.StatusCode {
+			r.add(StatusCodeChanged, o.Identifier, o.StatusCode, n.StatusCode, true)
+		}
+		if n.HTTPStatus != o.HTTPStatus {
+			r.add(HTTPStatusChanged, o.Identifier, fmt.Sprint(o.HTTPStatus), fmt.Sprint(n.HTTPStatus), true)
+		}
+		if n.RetryAdvice != o.RetryAdvice {
+			r.add(RetryAdviceChanged, o.Identifier, string(o.RetryAdvice), string(n.RetryAdvice), true)
+		}
+		if n.Deprecated && !o.Deprecated {
+			r.add(CaseDeprecated, o.Identifier, "", n.Replacement, false)
+		}
+	}
+	for _, n := range newM.Cases {
+		if !oldIDs[n.Identifier] {
+			r.add(CaseAdded, n.Identifier, "", n.StatusCode, false)
+		}
+	}
+
+	sort.SliceStable(r.Changes, func(i, j int) bool {
+		return r.Changes[i].Identifier < r.Changes[j].Identifier
+	})
+	return r
+}
+
+func (r *CompatReport) add(kind ChangeKind, identifier string, oldValue string, newValue string, breaking bool) {
+	r.Changes = append(r.Changes, Change{Kind: kind, Identifier: identifier, Old: oldValue, New: newValue, Breaking: breaking})
+}
@@ -0,0 +1,67 @@
+package numcase
+
+import (
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareManifests(t *testing.T) {
+	old := &Manifest{Cases: []CaseEntry{
+		{Identifier: "1_2_001", AppCode: 1, ModuleCode: 2, CaseCode: 1, StatusCode: "InvalidArgument",
+			HTTPStatus: 400, RetryAdvice: domainerr.NoAdvice},
+		{Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "NotFound",
+			HTTPStatus: 404, RetryAdvice: domainerr.NoAdvice},
+		{Identifier: "1_2_201", AppCode: 1, ModuleCode: 2, CaseCode: 201, StatusCode: "AlreadyExists",
+			HTTPStatus: 409, RetryAdvice: domainerr.NoAdvice},
+	}}
+
+	report := CompareManifests(old, old)
+	assert.Empty(t, report.Changes)
+	assert.False(t, report.Breaking())
+
+	added := &Manifest{Cases: append(append([]CaseEntry{}, old.Cases...), CaseEntry{
+		Identifier: "1_2_301", AppCode: 1, ModuleCode: 2, CaseCode: 301, StatusCode: "ServiceUnavailable",
+		HTTPStatus: 503, RetryAdvice: domainerr.JustRetryFailingCall,
+	})}
+	report = CompareManifests(old, added)
+	assert.Equal(t, []Change{
+		{Kind: CaseAdded, Identifier: "1_2_301", New: "ServiceUnavailable"},
+	}, report.Changes)
+	assert.False(t, report.Breaking())
+
+	changed := &Manifest{Cases: []CaseEntry{
+		// status code, HTTP status and retry advice changed
+		{Identifier: "1_2_001", AppCode: 1, ModuleCode: 2, CaseCode: 1, StatusCode: "FailedPrecondition",
+			HTTPStatus: 412, RetryAdvice: domainerr.NotRetryUntilStateFixed},
+		// identifier reused for another case code
+		{Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 102, StatusCode: "NotFound",
+			HTTPStatus: 404, RetryAdvice: domainerr.NoAdvice},
+		// 1_2_201 removed, and its case code reused
+
+		{Identifier: "1_2_202", AppCode: 1, ModuleCode: 2, CaseCode: 201, StatusCode: "AlreadyExists",
+			HTTPStatus: 409, RetryAdvice: domainerr.NoAdvice},
+	}}
+	report = CompareManifests(old, changed)
+	assert.True(t, report.Breaking())
+	assert.Equal(t, []Change{
+		{Kind: StatusCodeChanged, Identifier: "1_2_001", Old: "InvalidArgument", New: "FailedPrecondition", Breaking: true},
+		{Kind: HTTPStatusChanged, Identifier: "1_2_001", Old: "400", New: "412", Breaking: true},
+		{Kind: RetryAdviceChanged, Identifier: "1_2_001", Old: string(domainerr.NoAdvice), New: string(domainerr.NotRetryUntilStateFixed), Breaking: true},
+		{Kind: IdentifierReused, Identifier: "1_2_101", Old: "app 1 module 2 case 101", New: "app 1 module 2 case 102", Breaking: true},
+		{Kind: CaseRemoved, Identifier: "1_2_201", Old: "AlreadyExists", Breaking: true},
+		{Kind: CaseCodeReused, Identifier: "1_2_201", Old: "app 1 module 2 case 201", New: "1_2_202", Breaking: true},
+		{Kind: CaseAdded, Identifier: "1_2_202", New: "AlreadyExists"},
+	}, report.Changes)
+	assert.Equal(t, "BREAKING case_removed 1_2_201: AlreadyExists", report.Changes[4].String())
+	assert.Equal(t, "info case_added 1_2_202: AlreadyExists", report.Changes[6].String())
+
+	deprecated := &Manifest{Cases: append([]CaseEntry{}, old.Cases...)}
+	deprecated.Cases[2].Deprecated, deprecated.Cases[2].Replacement = true, "1_2_101"
+	report = CompareManifests(old, deprecated)
+	assert.Equal(t, []Change{
+		{Kind: CaseDeprecated, Identifier: "1_2_201", New: "1_2_101"},
+	}, report.Changes)
+	assert.False(t, report.Breaking())
+}
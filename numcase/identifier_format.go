@@ -0,0 +1,284 @@
+package numcase
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// IdentifierFormat controls how the app code, the module code and the case code of a NumCase are
+// formatted into its identifier, and how the identifier is parsed back. E.g.,
+//
+//	format            identifier of (app 1, module 2, case 105)
+//	default           01_002_0105
+//	WithSeparator("") 010020105
+//	WithPrefix("E"), WithSeparator("-")
+//	                  E01-002-0105
+//	Packed()          10020105
+//
+// The codes are left padded with zeros to the number of digits defined by the CodingStrategy,
+// except the leading code in packed mode.
+type IdentifierFormat struct {
+	separator string
+	prefix    string
+	radix     int
+	packed    bool
+}
+
+type IdentifierFormatOpt func(f *IdentifierFormat) error
+
+// WithSeparator sets the separator between the codes, which is "_" by default. An empty separator
+// concatenates the zero padded codes. It's ignored in packed mode.
+func WithSeparator(sep string) IdentifierFormatOpt {
+	return func(f *IdentifierFormat) error {
+		err := domainerr.CheckArgument(!strings.ContainsAny(sep, "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"),
+			"separator %q contains letters or digits", sep)
+		if err != nil {
+			return err
+		}
+		f.separator = sep
+		return nil
+	}
+}
+
+// WithPrefix sets the prefix of identifiers, e.g., "E". It must not end with a digit, so that it
+// can't be confused with the codes.
+func WithPrefix(prefix string) IdentifierFormatOpt {
+	return func(f *IdentifierFormat) error {
+		err := domainerr.CheckArgument(prefix == "" || !isDigit(prefix[len(prefix)-1]),
+			"prefix %q ends with a digit", prefix)
+		if err != nil {
+			return err
+		}
+		f.prefix = prefix
+		return nil
+	}
+}
+
+// WithRadix sets the radix of the codes, which is in [2, 36] and 10 by default. Digits beyond 9
+// are formatted as lowercase letters. The number of digits defined by the CodingStrategy is
+// counted in this radix.
+func WithRadix(radix int) IdentifierFormatOpt {
+	return func(f *IdentifierFormat) error {
+		err := domainerr.CheckArgument(radix >= 2 && radix <= 36, "radix %d not in [2, 36]", radix)
+		if err != nil {
+			return err
+		}
+		f.radix = radix
+		return nil
+	}
+}
+
+// Packed formats identifiers as the integer returned by NumCase.Int, i.e.,
+// appCode*radix^(m+c) + moduleCode*radix^c + caseCode, where m and c are the numbers of digits of
+// the module code and the case code.
+func Packed() IdentifierFormatOpt {
+	return func(f *IdentifierFormat) error {
+		f.packed = true
+		return nil
+	}
+}
+
+func NewIdentifierFormat(opts ...IdentifierFormatOpt) (*IdentifierFormat, error) {
+	f := &IdentifierFormat{separator: "_", radix: 10}
+	for _, setOpt := range opts {
+		if err := setOpt(f); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// DefaultIdentifierFormat separates the decimal codes with "_", e.g., 01_002_0105.
+var DefaultIdentifierFormat, _ = NewIdentifierFormat()
+
+func (f *IdentifierFormat) Separator() string {
+	return f.separator
+}
+
+func (f *IdentifierFormat) Prefix() string {
+	return f.prefix
+}
+
+func (f *IdentifierFormat) Radix() int {
+	return f.radix
+}
+
+func (f *IdentifierFormat) IsPacked() bool {
+	return f.packed
+}
+
+// format formats the identifier of the case with the given codes.
+func (f *IdentifierFormat) format(s *CodingStrategy, appCode int, moduleCode int, caseCode int) string {
+	if f.packed {
+		return f.prefix + strconv.FormatInt(s.pack(appCode, moduleCode, caseCode), f.radix)
+	}
+	appID, moduleID := f.formatGroups(s, appCode, moduleCode)
+	caseID := f.pad(caseCode, s.numDigitsOfCaseCode)
+	switch {
+	case moduleID != "":
+		return moduleID + f.separator + caseID
+	case appID != "":
+		return appID + f.separator + caseID
+	default:
+		return f.prefix + caseID
+	}
+}
+
+// formatGroups formats the identifiers of the GroupCase of the given app and module. An identifier
+// is empty if the coding strategy has no such code. Group identifiers are never packed, but in
+// packed mode the codes are concatenated without the separator.
+func (f *IdentifierFormat) formatGroups(s *CodingStrategy, appCode int, moduleCode int) (appID string, moduleID string) {
+	if s.numDigitsOfAppCode > 0 {
+		appID = f.prefix + f.pad(appCode, s.numDigitsOfAppCode)
+	}
+	if s.numDigitsOfModuleCode > 0 {
+		sep := f.separator
+		if f.packed {
+			sep = ""
+		}
+		if appID != "" {
+			moduleID = appID + sep + f.pad(moduleCode, s.numDigitsOfModuleCode)
+		} else {
+			moduleID = f.prefix + f.pad(moduleCode, s.numDigitsOfModuleCode)
+		}
+	}
+	return appID, moduleID
+}
+
+func (f *IdentifierFormat) pad(num int, minLen int) string {
+	return padLeftZeros(strconv.FormatInt(int64(num), f.radix), minLen)
+}
+
+// parse parses the codes from the given identifier formatted by this format.
+func (f *IdentifierFormat) parse(s *CodingStrategy, identifier string) (appCode int, moduleCode int, caseCode int, err error) {
+	err = domainerr.CheckArgument(strings.HasPrefix(identifier, f.prefix),
+		"identifier %q doesn't start with prefix %q", identifier, f.prefix)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	body := identifier[len(f.prefix):]
+	if f.packed {
+		return f.parsePacked(s, identifier, body)
+	}
+
+	var digitsOfParts []int
+	if s.numDigitsOfAppCode > 0 {
+		digitsOfParts = append(digitsOfParts, s.numDigitsOfAppCode)
+	}
+	if s.numDigitsOfModuleCode > 0 {
+		digitsOfParts = append(digitsOfParts, s.numDigitsOfModuleCode)
+	}
+	digitsOfParts = append(digitsOfParts, s.numDigitsOfCaseCode)
+
+	var parts []string
+	if f.separator != "" {
+		parts = strings.Split(body, f.separator)
+		err = domainerr.CheckArgument(len(parts) == len(digitsOfParts),
+			"identifier %q doesn't consist of %d parts separated by %q", identifier, len(digitsOfParts), f.separator)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	} else {
+		numDigits := 0
+		for _, n := range digitsOfParts {
+			numDigits += n
+		}
+		err = domainerr.CheckArgument(len(body) == numDigits,
+			"identifier %q doesn't consist of %d digits", identifier, numDigits)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, n := range digitsOfParts {
+			parts = append(parts, body[:n])
+			body = body[n:]
+		}
+	}
+
+	codes := make([]int, len(parts))
+	for i, part := range parts {
+		err = domainerr.CheckArgument(len(part) == digitsOfParts[i] && f.isDigits(part),
+			"part %q of identifier %q isn't a number of %d digits", part, identifier, digitsOfParts[i])
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		code, _ := strconv.ParseInt(part, f.radix, 64)
+		codes[i] = int(code)
+	}
+
+	i := 0
+	if s.numDigitsOfAppCode > 0 {
+		appCode = codes[i]
+		i++
+	}
+	if s.numDigitsOfModuleCode > 0 {
+		moduleCode = codes[i]
+		i++
+	}
+	return appCode, moduleCode, codes[i], nil
+}
+
+func (f *IdentifierFormat) parsePacked(s *CodingStrategy, identifier string, body string) (appCode int, moduleCode int, caseCode int, err error) {
+	numDigits := s.numDigitsOfAppCode + s.numDigitsOfModuleCode + s.numDigitsOfCaseCode
+	err = domainerr.CheckArgument(body != "" && len(body) <= numDigits && f.isDigits(body),
+		"identifier %q isn't a number of at most %d digits", identifier, numDigits)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	v, _ := strconv.ParseInt(body, f.radix, 64)
+	err = domainerr.CheckArgument(strconv.FormatInt(v, f.radix) == body,
+		"identifier %q isn't in canonical form", identifier)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	caseUnit := intPow(f.radix, s.numDigitsOfCaseCode)
+	moduleUnit := intPow(f.radix, s.numDigitsOfModuleCode)
+	caseCode = int(v % caseUnit)
+	moduleCode = int(v / caseUnit % moduleUnit)
+	appCode = int(v / caseUnit / moduleUnit)
+	return appCode, moduleCode, caseCode, nil
+}
+
+// isDigits tells if s consists of the digits of the radix only. Digits beyond 9 must be lowercase.
+func (f *IdentifierFormat) isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		var d int
+		switch c := s[i]; {
+		case isDigit(c):
+			d = int(c - '0')
+		case c >= 'a' && c <= 'z':
+			d = int(c-'a') + 10
+		default:
+			return false
+		}
+		if d >= f.radix {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func padLeftZeros(s string, minLen int) string {
+	if len(s) >= minLen {
+		return s
+	}
+	return strings.Repeat("0", minLen-len(s)) + s
+}
+
+// intPow returns base^exp, or -1 if it overflows int64.
+func intPow(base int, exp int) int64 {
+	result := int64(1)
+	for i := 0; i < exp; i++ {
+		if result > (1<<63-1)/int64(base) {
+			return -1
+		}
+		result *= int64(base)
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package numcase
+
+import (
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strategyWithFormat(t *testing.T, opts ...IdentifierFormatOpt) *CodingStrategy {
+	format, err := NewIdentifierFormat(opts...)
+	assert.Nil(t, err)
+	s, err := NewCodingStrategyBuilder().
+		NumDigitsOfAppCode(2).
+		NumDigitsOfModuleCode(3).
+		NumDigitsOfCaseCode(4).
+		StatusCodeMapper(NewCodeMapper(&DefaultCodeMapper{})).
+		IdentifierFormat(format).
+		Build()
+	assert.Nil(t, err)
+	return s
+}
+
+func TestIdentifierFormat_Options(t *testing.T) {
+	f, err := NewIdentifierFormat()
+	assert.Nil(t, err)
+	assert.Equal(t, "_", f.Separator())
+	assert.Equal(t, "", f.Prefix())
+	assert.Equal(t, 10, f.Radix())
+	assert.False(t, f.IsPacked())
+
+	_, err = NewIdentifierFormat(WithRadix(1))
+	assert.Equal(t, "illegal argument: radix 1 not in [2, 36]", err.Error())
+	_, err = NewIdentifierFormat(WithRadix(37))
+	assert.NotNil(t, err)
+	_, err = NewIdentifierFormat(WithSeparator("x"))
+	assert.Equal(t, `illegal argument: separator "x" contains letters or digits`, err.Error())
+	_, err = NewIdentifierFormat(WithPrefix("E1"))
+	assert.Equal(t, `illegal argument: prefix "E1" ends with a digit`, err.Error())
+}
+
+func TestIdentifierFormat_FormatAndParse(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []IdentifierFormatOpt
+		id   string
+		app  string
+		mod  string
+	}{
+		{"default", nil, "01_002_0105", "01", "01_002"},
+		{"no separator", []IdentifierFormatOpt{WithSeparator("")}, "010020105", "01", "01002"},
+		{"prefix", []IdentifierFormatOpt{WithPrefix("E"), WithSeparator("-")}, "E01-002-0105", "E01", "E01-002"},
+		{"radix", []IdentifierFormatOpt{WithRadix(16)}, "01_002_0069", "01", "01_002"},
+		{"packed", []IdentifierFormatOpt{Packed()}, "10020105", "01", "01002"},
+		{"packed with prefix", []IdentifierFormatOpt{Packed(), WithPrefix("E")}, "E10020105", "E01", "E01002"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := strategyWithFormat(t, tt.opts...)
+			f, _ := NewFactory(s, WithAppCode(1), WithModuleCode(2))
+			c, err := f.NewNotFound(105)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.id, c.Identifier())
+			assert.Equal(t, tt.app, f.AppCase().Identifier())
+			assert.Equal(t, tt.mod, f.ModuleCase().Identifier())
+
+			parsed, err := s.Parse(tt.id)
+			assert.Nil(t, err)
+			assert.Equal(t, c, parsed)
+		})
+	}
+}
+
+func TestIdentifierFormat_Parse_Illegal(t *testing.T) {
+	s := strategyWithFormat(t, WithPrefix("E"), WithSeparator(""))
+	_, err := s.Parse("010020105")
+	assert.Equal(t, `illegal argument: identifier "010020105" doesn't start with prefix "E"`, err.Error())
+	_, err = s.Parse("E0100201050")
+	assert.Equal(t, `illegal argument: identifier "E0100201050" doesn't consist of 9 digits`, err.Error())
+	_, err = s.Parse("E01002010x")
+	assert.NotNil(t, err)
+
+	s = strategyWithFormat(t, WithRadix(16))
+	_, err = s.Parse("01_002_006A")
+	assert.Equal(t, `illegal argument: part "006A" of identifier "01_002_006A" isn't a number of 4 digits`, err.Error())
+	_, err = s.Parse("01_002_006g")
+	assert.NotNil(t, err)
+
+	s = strategyWithFormat(t, Packed())
+	_, err = s.Parse("010020105")
+	assert.Equal(t, `illegal argument: identifier "010020105" isn't in canonical form`, err.Error())
+	_, err = s.Parse("1234567890")
+	assert.Equal(t, `illegal argument: identifier "1234567890" isn't a number of at most 9 digits`, err.Error())
+	_, err = s.Parse("")
+	assert.NotNil(t, err)
+	_, err = s.Parse("10029999")
+	assert.Equal(t, `illegal argument: case code 9999 of identifier "10029999" isn't in any CaseCodeSegment`, err.Error())
+}
+
+func TestNumCase_Int(t *testing.T) {
+	c, _ := strategyWithFormat(t).Parse("01_002_0105")
+	assert.Equal(t, int64(10020105), c.Int())
+	c, _ = strategyWithFormat(t, WithRadix(16)).Parse("01_002_0069")
+	assert.Equal(t, int64(0x010020069), c.Int())
+	c, _ = csWithoutAppCodeAndModuleCode.Parse("301")
+	assert.Equal(t, int64(301), c.Int())
+	assert.Equal(t, domainerr.CodeFailedPrecondition, c.StatusCode())
+}
+
+func TestCodingStrategyBuilder_DigitsOverflow(t *testing.T) {
+	_, err := NewCodingStrategyBuilder().
+		NumDigitsOfAppCode(10).
+		NumDigitsOfModuleCode(5).
+		NumDigitsOfCaseCode(4).
+		StatusCodeMapper(NewCodeMapper(&DefaultCodeMapper{})).
+		Build()
+	assert.Equal(t, "illegal argument: 19 digits in radix 10 overflow int64", err.Error())
+}
@@ -0,0 +1,269 @@
+package numcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// Manifest lists the apps, the modules and the cases of one or more services. It is exported by
+// Catalog.Manifest, and can be encoded as JSON to be collected from several services and merged by
+// MergeManifests.
+type Manifest struct {
+	Apps    []AppEntry    `json:"apps"`
+	Modules []ModuleEntry `json:"modules"`
+	Cases   []CaseEntry   `json:"cases"`
+}
+
+// AppEntry records that an app is owned by a team.
+type AppEntry struct {
+	// Service is the service declaring this entry.
+	Service string `json:"service,omitempty"`
+	AppCode int    `json:"appCode"`
+	Owner
+}
+
+// ModuleEntry records that a module of an app is owned by a team.
+type ModuleEntry struct {
+	// Service is the service declaring this entry.
+	Service    string `json:"service,omitempty"`
+	AppCode    int    `json:"appCode"`
+	ModuleCode int    `json:"moduleCode"`
+	Owner
+}
+
+// CaseEntry describes a case.
+type CaseEntry struct {
+	// Service is the service declaring this entry.
+	Service    string `json:"service,omitempty"`
+	Identifier string `json:"identifier"`
+	AppCode    int    `json:"appCode"`
+	ModuleCode int    `json:"moduleCode"`
+	CaseCode   int    `json:"caseCode"`
+	// StatusCode is the name of the status code of the case, i.e., Code.Name().
+	StatusCode string `json:"statusCode"`
+	// HTTPStatus is the HTTP status code the status code is mapped to, or 0 if there is none.
+	HTTPStatus int `json:"httpStatus,omitempty"`
+	// RetryAdvice is the retry advice of the status code.
+	RetryAdvice domainerr.RetryAdvice `json:"retryAdvice,omitempty"`
+	// Since is the version in which the case was introduced.
+	Since string `json:"since,omitempty"`
+	// Deprecated tells if the case is deprecated.
+	Deprecated bool `json:"deprecated,omitempty"`
+	// Replacement is the identifier of the case replacing a deprecated case.
+	Replacement string `json:"replacement,omitempty"`
+	// Visibility is the visibility of the case, i.e., public or internal.
+	Visibility domainerr.Visibility `json:"visibility,omitempty"`
+}
+
+func caseEntryOf(service string, c *NumCase) CaseEntry {
+	e := CaseEntry{
+		Service:     service,
+		Identifier:  c.Identifier(),
+		AppCode:     c.appCode,
+		ModuleCode:  c.moduleCode,
+		CaseCode:    c.caseCode,
+		StatusCode:  c.statusCode.Name(),
+		RetryAdvice: domainerr.NewWithCode(c.statusCode).RetryAdvice(),
+	}
+	if httpStatus := c.statusCode.ToHTTPStatus(); httpStatus != nil {
+		e.HTTPStatus = httpStatus.Code()
+	}
+	l := c.Lifecycle()
+	e.Since, e.Deprecated, e.Visibility = l.Since, l.Deprecated, l.Visibility
+	if domainerr.NotNil(l.Replacement) {
+		e.Replacement = l.Replacement.Identifier()
+	}
+	return e
+}
+
+// Status returns the lifecycle status of the case shown in docs, e.g., "deprecated, use 1_2_102
+// instead, since v1.2.0, internal", or "active" for a public case which isn't deprecated.
+func (e *CaseEntry) Status() string {
+	parts := []string{"active"}
+	if e.Deprecated {
+		parts[0] = "deprecated"
+		if e.Replacement != "" {
+			parts[0] = fmt.Sprintf("deprecated, use %s instead", e.Replacement)
+		}
+	}
+	if e.Since != "" {
+		parts = append(parts, "since "+e.Since)
+	}
+	if e.Visibility == domainerr.VisibilityInternal {
+		parts = append(parts, string(domainerr.VisibilityInternal))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (e *CaseEntry) key() caseKey {
+	return caseKey{moduleKey: moduleKey{appCode: e.AppCode, moduleCode: e.ModuleCode}, caseCode: e.CaseCode}
+}
+
+// sameAs tells if the two entries describe the same case, regardless of the services declaring
+// them.
+func (e *CaseEntry) sameAs(other *CaseEntry) bool {
+	e1, e2 := *e, *other
+	e1.Service, e2.Service = "", ""
+	return e1 == e2
+}
+
+// LoadManifestJSON decodes a manifest from JSON.
+func LoadManifestJSON(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to decode Manifest: %w", err)
+	}
+	m.sort()
+	return m, nil
+}
+
+// WriteMarkdown writes the cases as a Markdown table, e.g., to generate the error code docs of a
+// service. The last column is the lifecycle status of each case, see CaseEntry.Status.
+func (m *Manifest) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("| Identifier | App | Module | Case | Status Code | HTTP Status | Status |\n")
+	b.WriteString("|---|---|---|---|---|---|---|\n")
+	for i := range m.Cases {
+		e := &m.Cases[i]
+		httpStatus := ""
+		if e.HTTPStatus != 0 {
+			httpStatus = strconv.Itoa(e.HTTPStatus)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %s | %s | %s |\n",
+			e.Identifier, e.AppCode, e.ModuleCode, e.CaseCode, e.StatusCode, httpStatus, e.Status())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sort sorts the entries by their codes, so that the manifest is deterministic.
+func (m *Manifest) sort() {
+	sort.SliceStable(m.Apps, func(i, j int) bool {
+		return m.Apps[i].AppCode < m.Apps[j].AppCode
+	})
+	sort.SliceStable(m.Modules, func(i, j int) bool {
+		mi, mj := m.Modules[i], m.Modules[j]
+		if mi.AppCode != mj.AppCode {
+			return mi.AppCode < mj.AppCode
+		}
+		return mi.ModuleCode < mj.ModuleCode
+	})
+	sort.SliceStable(m.Cases, func(i, j int) bool {
+		ki, kj := m.Cases[i].key(), m.Cases[j].key()
+		if ki.appCode != kj.appCode {
+			return ki.appCode < kj.appCode
+		}
+		if ki.moduleCode != kj.moduleCode {
+			return ki.moduleCode < kj.moduleCode
+		}
+		return ki.caseCode < kj.caseCode
+	})
+}
+
+// ConflictKind is the kind of a Conflict.
+type ConflictKind string
+
+const (
+	// AppConflict means an app is claimed by different teams.
+	AppConflict ConflictKind = "app"
+	// ModuleConflict means a module is claimed by different teams.
+	ModuleConflict ConflictKind = "module"
+	// CaseConflict means a case code is used by different cases, or an identifier is used for
+	// different case codes.
+	CaseConflict ConflictKind = "case"
+)
+
+// Conflict is a collision found by MergeManifests.
+type Conflict struct {
+	Kind ConflictKind `json:"kind"`
+	// Services are the services declaring the conflicting entries.
+	Services []string `json:"services"`
+	Reason   string   `json:"reason"`
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s conflict between %s: %s", c.Kind, strings.Join(c.Services, ", "), c.Reason)
+}
+
+// MergeManifests merges the given manifests into one, and reports the conflicts among them.
+// Identical entries declared by several services, e.g., cases of a shared library, are merged into
+// the first one. Of conflicting entries, only the first one is kept in the merged manifest.
+func MergeManifests(manifests ...*Manifest) (*Manifest, []Conflict) {
+	merged := &Manifest{}
+	var conflicts []Conflict
+
+	apps := make(map[int]AppEntry)
+	modules := make(map[moduleKey]ModuleEntry)
+	cases := make(map[caseKey]CaseEntry)
+	caseIDs := make(map[string]CaseEntry)
+	for _, m := range manifests {
+		for _, e := range m.Apps {
+			first, found := apps[e.AppCode]
+			switch {
+			case !found:
+				apps[e.AppCode] = e
+				merged.Apps = append(merged.Apps, e)
+			case first.Owner != e.Owner:
+				conflicts = append(conflicts, Conflict{
+					Kind:     AppConflict,
+					Services: []string{first.Service, e.Service},
+					Reason: fmt.Sprintf("app %d is claimed by team %s and team %s",
+						e.AppCode, first.Team, e.Team),
+				})
+			}
+		}
+
+		for _, e := range m.Modules {
+			key := moduleKey{appCode: e.AppCode, moduleCode: e.ModuleCode}
+			first, found := modules[key]
+			switch {
+			case !found:
+				modules[key] = e
+				merged.Modules = append(merged.Modules, e)
+			case first.Owner != e.Owner:
+				conflicts = append(conflicts, Conflict{
+					Kind:     ModuleConflict,
+					Services: []string{first.Service, e.Service},
+					Reason: fmt.Sprintf("module %d of app %d is claimed by team %s and team %s",
+						e.ModuleCode, e.AppCode, first.Team, e.Team),
+				})
+			}
+		}
+
+		for _, e := range m.Cases {
+			e := e
+			first, found := cases[e.key()]
+			if found {
+				if !first.sameAs(&e) {
+					conflicts = append(conflicts, Conflict{
+						Kind:     CaseConflict,
+						Services: []string{first.Service, e.Service},
+						Reason: fmt.Sprintf("%s is used by case %s(%s) and case %s(%s)",
+							e.key(), first.Identifier, first.StatusCode, e.Identifier, e.StatusCode),
+					})
+				}
+				continue
+			}
+			if first, found = caseIDs[e.Identifier]; found {
+				conflicts = append(conflicts, Conflict{
+					Kind:     CaseConflict,
+					Services: []string{first.Service, e.Service},
+					Reason: fmt.Sprintf("identifier %s is used for %s and %s",
+						e.Identifier, first.key(), e.key()),
+				})
+				continue
+			}
+			cases[e.key()] = e
+			caseIDs[e.Identifier] = e
+			merged.Cases = append(merged.Cases, e)
+		}
+	}
+	merged.sort()
+	return merged, conflicts
+}
@@ -0,0 +1,120 @@
+package numcase
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest_JSON(t *testing.T) {
+	m := &Manifest{
+		Apps:    []AppEntry{{Service: "order-service", AppCode: 1, Owner: Owner{Team: "trade"}}},
+		Modules: []ModuleEntry{{Service: "order-service", AppCode: 1, ModuleCode: 2, Owner: Owner{Team: "trade-order"}}},
+		Cases: []CaseEntry{
+			{Service: "order-service", Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "NotFound"},
+		},
+	}
+	b, err := json.Marshal(m)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"apps": [{"service": "order-service", "appCode": 1, "team": "trade"}],
+		"modules": [{"service": "order-service", "appCode": 1, "moduleCode": 2, "team": "trade-order"}],
+		"cases": [{"service": "order-service", "identifier": "1_2_101", "appCode": 1, "moduleCode": 2,
+			"caseCode": 101, "statusCode": "NotFound"}]
+	}`, string(b))
+
+	loaded, err := LoadManifestJSON(b)
+	assert.Nil(t, err)
+	assert.Equal(t, m, loaded)
+	_, err = LoadManifestJSON([]byte(`[`))
+	assert.NotNil(t, err)
+}
+
+func TestMergeManifests(t *testing.T) {
+	order := &Manifest{
+		Apps:    []AppEntry{{Service: "order", AppCode: 1, Owner: Owner{Team: "trade"}}},
+		Modules: []ModuleEntry{{Service: "order", AppCode: 1, ModuleCode: 2, Owner: Owner{Team: "trade-order"}}},
+		Cases: []CaseEntry{
+			{Service: "order", Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "NotFound"},
+			{Service: "order", Identifier: "1_2_001", AppCode: 1, ModuleCode: 2, CaseCode: 1, StatusCode: "InvalidArgument"},
+		},
+	}
+	cart := &Manifest{
+		Apps:    []AppEntry{{Service: "cart", AppCode: 1, Owner: Owner{Team: "trade"}}},
+		Modules: []ModuleEntry{{Service: "cart", AppCode: 1, ModuleCode: 3, Owner: Owner{Team: "trade-cart"}}},
+		Cases: []CaseEntry{
+			{Service: "cart", Identifier: "1_3_101", AppCode: 1, ModuleCode: 3, CaseCode: 101, StatusCode: "NotFound"},
+			// shared with order
+			{Service: "cart", Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "NotFound"},
+		},
+	}
+	merged, conflicts := MergeManifests(order, cart)
+	assert.Empty(t, conflicts)
+	assert.Len(t, merged.Apps, 1)
+	assert.Len(t, merged.Modules, 2)
+	assert.Equal(t, []string{"1_2_001", "1_2_101", "1_3_101"}, caseIDs(merged))
+
+	payment := &Manifest{
+		Apps: []AppEntry{{Service: "payment", AppCode: 1, Owner: Owner{Team: "payment"}}},
+		Modules: []ModuleEntry{
+			{Service: "payment", AppCode: 1, ModuleCode: 2, Owner: Owner{Team: "payment"}},
+		},
+		Cases: []CaseEntry{
+			{Service: "payment", Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "DataLoss"},
+			{Service: "payment", Identifier: "1_3_101", AppCode: 1, ModuleCode: 2, CaseCode: 102, StatusCode: "NotFound"},
+		},
+	}
+	merged, conflicts = MergeManifests(order, cart, payment)
+	assert.Equal(t, []string{"1_2_001", "1_2_101", "1_3_101"}, caseIDs(merged))
+	assert.Equal(t, []string{
+		"app conflict between order, payment: app 1 is claimed by team trade and team payment",
+		"module conflict between order, payment: module 2 of app 1 is claimed by team trade-order and team payment",
+		"case conflict between order, payment: app 1 module 2 case 101 is used by case 1_2_101(NotFound) and case 1_2_101(DataLoss)",
+		"case conflict between cart, payment: identifier 1_3_101 is used for app 1 module 3 case 101 and app 1 module 2 case 102",
+	}, conflictStrings(conflicts))
+}
+
+func caseIDs(m *Manifest) []string {
+	ids := make([]string, len(m.Cases))
+	for i, e := range m.Cases {
+		ids[i] = e.Identifier
+	}
+	return ids
+}
+
+func conflictStrings(conflicts []Conflict) []string {
+	s := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		s[i] = c.String()
+	}
+	return s
+}
+
+func TestCaseEntry_Status(t *testing.T) {
+	assert.Equal(t, "active", (&CaseEntry{}).Status())
+	assert.Equal(t, "active, since v1.2.0", (&CaseEntry{Since: "v1.2.0"}).Status())
+	assert.Equal(t, "deprecated", (&CaseEntry{Deprecated: true}).Status())
+	assert.Equal(t, "deprecated, use 1_2_102 instead, since v1.0.0, internal", (&CaseEntry{
+		Since: "v1.0.0", Deprecated: true, Replacement: "1_2_102", Visibility: domainerr.VisibilityInternal,
+	}).Status())
+}
+
+func TestManifest_WriteMarkdown(t *testing.T) {
+	m := &Manifest{Cases: []CaseEntry{
+		{Identifier: "1_2_101", AppCode: 1, ModuleCode: 2, CaseCode: 101, StatusCode: "NotFound", HTTPStatus: 404,
+			Deprecated: true, Replacement: "1_2_102"},
+		{Identifier: "1_2_102", AppCode: 1, ModuleCode: 2, CaseCode: 102, StatusCode: "NotFound", HTTPStatus: 404,
+			Since: "v1.2.0"},
+	}}
+	var b strings.Builder
+	assert.Nil(t, m.WriteMarkdown(&b))
+	assert.Equal(t, `| Identifier | App | Module | Case | Status Code | HTTP Status | Status |
+|---|---|---|---|---|---|---|
+| 1_2_101 | 1 | 2 | 101 | NotFound | 404 | deprecated, use 1_2_102 instead |
+| 1_2_102 | 1 | 2 | 102 | NotFound | 404 | active, since v1.2.0 |
+`, b.String())
+}
@@ -11,18 +11,48 @@ type NumCase struct {
 
 	identifier string
 	statusCode domainerr.Code
+	parent     *GroupCase
+	packed     int64
+	meta       domainerr.CaseMeta
 }
 
-func newNumCase(appCode int, moduleCode int, caseCode int, identifier string, statusCode domainerr.Code) *NumCase {
+func newNumCase(appCode int, moduleCode int, caseCode int, identifier string, statusCode domainerr.Code,
+	parent *GroupCase) *NumCase {
 	return &NumCase{
 		appCode:    appCode,
 		moduleCode: moduleCode,
 		caseCode:   caseCode,
 		identifier: identifier,
 		statusCode: statusCode,
+		parent:     parent,
 	}
 }
 
+// AppCode returns the code of the app owning this case, which is 0 if the coding strategy has no
+// app code.
+func (c *NumCase) AppCode() int {
+	return c.appCode
+}
+
+// ModuleCode returns the code of the module owning this case, which is 0 if the coding strategy has
+// no module code.
+func (c *NumCase) ModuleCode() int {
+	return c.moduleCode
+}
+
+// CaseCode returns the code of this case within its module.
+func (c *NumCase) CaseCode() int {
+	return c.caseCode
+}
+
+// Int returns the codes packed into an integer, i.e.,
+// appCode*radix^(m+c) + moduleCode*radix^c + caseCode, where m and c are the numbers of digits of
+// the module code and the case code, for systems that want numeric error codes. E.g., 10020105 for
+// 01_002_0105.
+func (c *NumCase) Int() int64 {
+	return c.packed
+}
+
 func (c *NumCase) Identifier() string {
 	return c.identifier
 }
@@ -30,3 +60,63 @@ func (c *NumCase) Identifier() string {
 func (c *NumCase) StatusCode() domainerr.Code {
 	return c.statusCode
 }
+
+// Lifecycle returns the lifecycle metadata of this case. The Visibility is never empty.
+func (c *NumCase) Lifecycle() domainerr.Lifecycle {
+	return c.meta.Lifecycle()
+}
+
+// Fault returns the fault overriding the one of the status code, or "" if it isn't overridden.
+func (c *NumCase) Fault() domainerr.Fault {
+	return c.meta.Fault()
+}
+
+// Severity returns the severity overriding the one of the status code, or 0 if it isn't
+// overridden.
+func (c *NumCase) Severity() domainerr.Severity {
+	return c.meta.Severity()
+}
+
+// Parent returns the GroupCase of the module of this case, or the one of the app if the coding
+// strategy has no module code. It returns nil if the coding strategy has neither.
+func (c *NumCase) Parent() domainerr.Case {
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent
+}
+
+// GroupCase is the parent of all the cases of an app or a module, which is derived from the app
+// code and the module code. E.g., cases 01_002_0105 and 01_002_0301 are children of module case
+// 01_002, which is a child of app case 01. It lets callers handle all the errors of an app or a
+// module with domainerr.CaseIsA.
+//
+// A GroupCase spans cases of various status codes, so its StatusCode is CodeUnknown.
+type GroupCase struct {
+	identifier string
+	parent     *GroupCase
+}
+
+func (c *GroupCase) Identifier() string {
+	return c.identifier
+}
+
+func (c *GroupCase) StatusCode() domainerr.Code {
+	return domainerr.CodeUnknown
+}
+
+func (c *GroupCase) Parent() domainerr.Case {
+	if c.parent == nil {
+		return nil
+	}
+	return c.parent
+}
+
+// parentCaseOf returns the parent of the cases of a module, which is moduleCase if present, or
+// appCase otherwise.
+func parentCaseOf(appCase *GroupCase, moduleCase *GroupCase) *GroupCase {
+	if moduleCase != nil {
+		return moduleCase
+	}
+	return appCase
+}
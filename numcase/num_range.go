@@ -48,6 +48,58 @@ func (r *NumRange) includeRange(r1 *NumRange) bool {
 	return r.start <= r1.start && r1.end <= r.end
 }
 
+// Len returns the count of numbers in this range.
+func (r *NumRange) Len() int {
+	return r.end - r.start + 1
+}
+
+// Overlaps tells if this range and r1 have any number in common.
+func (r *NumRange) Overlaps(r1 *NumRange) bool {
+	return r.start <= r1.end && r1.start <= r.end
+}
+
+// Intersect returns the numbers in common of this range and r1, or nil if they don't overlap.
+func (r *NumRange) Intersect(r1 *NumRange) *NumRange {
+	if !r.Overlaps(r1) {
+		return nil
+	}
+	start, end := r.start, r.end
+	if r1.start > start {
+		start = r1.start
+	}
+	if r1.end < end {
+		end = r1.end
+	}
+	return &NumRange{start: start, end: end}
+}
+
+// Split splits this range into n consecutive ranges whose lengths differ by at most 1. The first
+// Len()%n ranges are one longer than the others. E.g., [1, 10] is split into [1, 4], [5, 7] and
+// [8, 10] for n = 3.
+func (r *NumRange) Split(n int) ([]*NumRange, error) {
+	err := domainerr.CheckArgument(n > 0, "n <= 0")
+	if err != nil {
+		return nil, err
+	}
+	err = domainerr.CheckArgument(n <= r.Len(), "range %s is shorter than %d", r.String(), n)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]*NumRange, n)
+	size, remainder := r.Len()/n, r.Len()%n
+	start := r.start
+	for i := range parts {
+		end := start + size - 1
+		if i < remainder {
+			end++
+		}
+		parts[i] = &NumRange{start: start, end: end}
+		start = end + 1
+	}
+	return parts, nil
+}
+
 func (r *NumRange) String() string {
 	return fmt.Sprintf("[%d, %d]", r.start, r.end)
 }
@@ -74,3 +74,61 @@ func TestNumRange_String(t *testing.T) {
 	}
 	assert.Equal(t, "[1, 10]", r.String())
 }
+
+func TestNumRange_Len(t *testing.T) {
+	r, _ := NewNumRange(1, 50)
+	assert.Equal(t, 50, r.Len())
+	r, _ = NewNumRange(7, 7)
+	assert.Equal(t, 1, r.Len())
+}
+
+func TestNumRange_OverlapsAndIntersect(t *testing.T) {
+	r, _ := NewNumRange(10, 20)
+	for _, tt := range []struct {
+		start, end int
+		intersect  string
+	}{
+		{0, 9, ""},
+		{21, 30, ""},
+		{0, 10, "[10, 10]"},
+		{20, 30, "[20, 20]"},
+		{12, 15, "[12, 15]"},
+		{5, 25, "[10, 20]"},
+		{15, 25, "[15, 20]"},
+	} {
+		r1, _ := NewNumRange(tt.start, tt.end)
+		assert.Equal(t, tt.intersect != "", r.Overlaps(r1), "%s", r1)
+		assert.Equal(t, tt.intersect != "", r1.Overlaps(r), "%s", r1)
+		if tt.intersect == "" {
+			assert.Nil(t, r.Intersect(r1))
+		} else {
+			assert.Equal(t, tt.intersect, r.Intersect(r1).String())
+			assert.Equal(t, tt.intersect, r1.Intersect(r).String())
+		}
+	}
+}
+
+func TestNumRange_Split(t *testing.T) {
+	r, _ := NewNumRange(1, 10)
+	parts, err := r.Split(3)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"[1, 4]", "[5, 7]", "[8, 10]"}, rangeStrings(parts))
+	parts, _ = r.Split(1)
+	assert.Equal(t, []string{"[1, 10]"}, rangeStrings(parts))
+	parts, _ = r.Split(10)
+	assert.Len(t, parts, 10)
+	assert.Equal(t, "[10, 10]", parts[9].String())
+
+	_, err = r.Split(0)
+	assert.Equal(t, "illegal argument: n <= 0", err.Error())
+	_, err = r.Split(11)
+	assert.Equal(t, "illegal argument: range [1, 10] is shorter than 11", err.Error())
+}
+
+func rangeStrings(ranges []*NumRange) []string {
+	s := make([]string, len(ranges))
+	for i, r := range ranges {
+		s[i] = r.String()
+	}
+	return s
+}
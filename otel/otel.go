@@ -0,0 +1,90 @@
+// Package otel records errors on OpenTelemetry spans with a consistent set of attributes.
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ikonglong/domainerr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The attribute keys recorded on spans.
+const (
+	AttrErrorType   = attribute.Key("error.type")
+	AttrCode        = attribute.Key("domainerr.code")
+	AttrCaseID      = attribute.Key("domainerr.case_id")
+	AttrHTTPStatus  = attribute.Key("domainerr.http_status")
+	AttrRetryAdvice = attribute.Key("domainerr.retry_advice")
+	AttrFault       = attribute.Key("domainerr.fault")
+	AttrSeverity    = attribute.Key("domainerr.severity")
+)
+
+// RecordError records the given error on the span in ctx, and returns the error as is, so that it
+// can be used in return statements:
+//
+//	return otel.RecordError(ctx, err)
+//
+// See RecordErrorOnSpan for what is recorded.
+func RecordError(ctx context.Context, err error) error {
+	return RecordErrorOnSpan(trace.SpanFromContext(ctx), err)
+}
+
+// RecordErrorOnSpan records the given error on the span, and returns the error as is. It adds an
+// exception event and the attributes returned by Attributes to the span. The span status is set
+// to Error only if the error is a server fault, see IsServerFault. Its fault, as overridden by
+// its specific case, is recorded as the AttrFault attribute. It does nothing for a nil err.
+func RecordErrorOnSpan(span trace.Span, err error) error {
+	if domainerr.IsNil(err) || !span.IsRecording() {
+		return err
+	}
+
+	attrs := Attributes(err)
+	span.SetAttributes(attrs...)
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	if IsServerFault(err) {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Attributes returns the attributes describing the given error.
+func Attributes(err error) []attribute.KeyValue {
+	var domainErr *domainerr.Error
+	if !errors.As(err, &domainErr) || domainerr.IsNil(domainErr) {
+		return []attribute.KeyValue{
+			AttrErrorType.String(fmt.Sprintf("%T", err)),
+			AttrFault.String(string(domainerr.FaultOf(err))),
+			AttrSeverity.String(domainerr.SeverityOf(err).String()),
+		}
+	}
+
+	status := domainErr.Status()
+	code := status.Code()
+	attrs := make([]attribute.KeyValue, 0, 7)
+	attrs = append(attrs, AttrErrorType.String(code.Name()), AttrCode.String(code.Name()))
+	if c := status.SpecificCase(); domainerr.NotNil(c) {
+		attrs = append(attrs, AttrCaseID.String(c.Identifier()))
+	}
+	if httpStatus := code.ToHTTPStatus(); httpStatus != nil {
+		attrs = append(attrs, AttrHTTPStatus.Int(httpStatus.Code()))
+	}
+	attrs = append(attrs, AttrRetryAdvice.String(string(status.RetryAdvice())),
+		AttrFault.String(string(status.Fault())), AttrSeverity.String(status.Severity().String()))
+	return attrs
+}
+
+// IsServerFault tells if the given error is a fault of the server, i.e., its code is one of
+// InternalError, Unknown, DataLoss, Unavailable and DeadlineExceeded, see Code.IsServerFault. An
+// error that isn't a *domainerr.Error is handled as an Unknown error.
+func IsServerFault(err error) bool {
+	var domainErr *domainerr.Error
+	if !errors.As(err, &domainErr) || domainerr.IsNil(domainErr) {
+		return true
+	}
+	code := domainErr.Status().Code()
+	return code.IsServerFault()
+}
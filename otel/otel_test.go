@@ -0,0 +1,123 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordInSpan(err error) tracetest.SpanStub {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+	RecordError(ctx, err)
+	span.End()
+	return exporter.GetSpans()[0]
+}
+
+func attrMap(attrs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(attrs))
+	for _, kv := range attrs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+type testCase struct{}
+
+func (c *testCase) Identifier() string {
+	return "order.purchase_limit_exceeded"
+}
+
+func (c *testCase) StatusCode() domainerr.Code {
+	return domainerr.CodeFailedPrecondition
+}
+
+func TestRecordError_ClientFault(t *testing.T) {
+	err := domainerr.NewFailedPrecondition().WithSpecificCase(&testCase{}).WithMessage("limit exceeded").Build()
+	span := recordInSpan(err)
+
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	attrs := attrMap(span.Attributes)
+	assert.Equal(t, "FailedPrecondition", attrs[AttrErrorType].AsString())
+	assert.Equal(t, "FailedPrecondition", attrs[AttrCode].AsString())
+	assert.Equal(t, "order.purchase_limit_exceeded", attrs[AttrCaseID].AsString())
+	assert.Equal(t, int64(400), attrs[AttrHTTPStatus].AsInt64())
+	assert.Equal(t, string(domainerr.NotRetryUntilStateFixed), attrs[AttrRetryAdvice].AsString())
+	assert.Equal(t, "client", attrs[AttrFault].AsString())
+	assert.Equal(t, "info", attrs[AttrSeverity].AsString())
+	assert.Len(t, span.Events, 1)
+	assert.Equal(t, "exception", span.Events[0].Name)
+}
+
+func TestRecordError_ServerFault(t *testing.T) {
+	for _, b := range []*domainerr.ErrorBuilder{
+		domainerr.NewInternalError(), domainerr.NewUnknownError(), domainerr.NewDataLoss(),
+		domainerr.NewUnavailable(), domainerr.NewDeadlineExceeded(),
+	} {
+		err := b.WithMessage("failed").Build()
+		span := recordInSpan(err)
+		assert.Equal(t, codes.Error, span.Status.Code, err.Error())
+		assert.Equal(t, err.Error(), span.Status.Description)
+		_, hasCaseID := attrMap(span.Attributes)[AttrCaseID]
+		assert.False(t, hasCaseID)
+	}
+}
+
+type dependencyCase struct {
+	testCase
+}
+
+func (c *dependencyCase) Fault() domainerr.Fault {
+	return domainerr.DependencyFault
+}
+
+func TestRecordError_FaultOverriddenByCase(t *testing.T) {
+	// The overridden fault is recorded, but doesn't change the span status.
+	span := recordInSpan(domainerr.NewFailedPrecondition().WithSpecificCase(&dependencyCase{}).Build())
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	assert.Equal(t, "dependency", attrMap(span.Attributes)[AttrFault].AsString())
+}
+
+func TestIsServerFault(t *testing.T) {
+	assert.True(t, IsServerFault(domainerr.NewInternalError().Build()))
+	assert.True(t, IsServerFault(fmt.Errorf("wrapped: %w", domainerr.NewUnavailable().Build())))
+	assert.True(t, IsServerFault(fmt.Errorf("boom")))
+	assert.False(t, IsServerFault(domainerr.NewUnimplemented().Build()))
+	assert.False(t, IsServerFault(domainerr.NewFailedPrecondition().WithSpecificCase(&dependencyCase{}).Build()))
+	assert.False(t, IsServerFault(domainerr.NewInvalidArgument().Build()))
+}
+
+func TestRecordError_WrappedDomainError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", domainerr.NewNotFound().Build())
+	span := recordInSpan(err)
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	assert.Equal(t, "NotFound", attrMap(span.Attributes)[AttrCode].AsString())
+}
+
+func TestRecordError_PlainError(t *testing.T) {
+	span := recordInSpan(fmt.Errorf("boom"))
+	assert.Equal(t, codes.Error, span.Status.Code)
+	attrs := attrMap(span.Attributes)
+	assert.Equal(t, "*errors.errorString", attrs[AttrErrorType].AsString())
+	assert.Equal(t, "server", attrs[AttrFault].AsString())
+	assert.Equal(t, "error", attrs[AttrSeverity].AsString())
+	_, hasCode := attrs[AttrCode]
+	assert.False(t, hasCode)
+}
+
+func TestRecordError_NilError(t *testing.T) {
+	var nilErr *domainerr.Error
+	assert.Nil(t, RecordError(context.Background(), nil))
+	span := recordInSpan(nilErr)
+	assert.Equal(t, codes.Unset, span.Status.Code)
+	assert.Empty(t, span.Attributes)
+	assert.Empty(t, span.Events)
+}
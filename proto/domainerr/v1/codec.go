@@ -0,0 +1,159 @@
+// Package domainerrv1 contains the protobuf wire format of domainerr statuses and errors, which
+// lets services in other languages produce and consume them, and the codec for the format.
+package domainerrv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative domainerr/v1/status.proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// FromStatus converts the given status to its wire format. Multiple details, i.e., an []any, are
+// packed one by one. Details that are proto messages are packed as they are, and the other
+// details are encoded as google.protobuf.Value in JSON form.
+func FromStatus(s *domainerr.Status) (*Status, error) {
+	code := s.Code()
+	pb := &Status{
+		Code:    int32(code.Value()),
+		Message: s.Message(),
+	}
+	if c := s.SpecificCase(); domainerr.NotNil(c) {
+		pb.CaseId = c.Identifier()
+		pb.CaseAncestors = domainerr.AncestorIDs(c)
+		pb.CaseDeprecation = domainerr.DeprecationNotice(c)
+	}
+	for _, d := range s.DetailList() {
+		a, err := packDetails(d)
+		if err != nil {
+			return nil, err
+		}
+		pb.Details = append(pb.Details, a)
+	}
+	return pb, nil
+}
+
+func packDetails(details any) (*anypb.Any, error) {
+	if m, ok := details.(proto.Message); ok {
+		return anypb.New(m)
+	}
+
+	b, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode details as JSON: %w", err)
+	}
+	v := &structpb.Value{}
+	if err = v.UnmarshalJSON(b); err != nil {
+		return nil, fmt.Errorf("failed to encode details as google.protobuf.Value: %w", err)
+	}
+	return anypb.New(v)
+}
+
+// FromError converts the status of the given *domainerr.Error to its wire format. If withDebugInfo
+// is true, the cause chain with stack traces is encoded as DebugInfo, which must not be sent to
+// untrusted clients. It returns (nil, nil) for a nil err.
+func FromError(err *domainerr.Error, withDebugInfo bool) (*Status, error) {
+	if domainerr.IsNil(err) {
+		return nil, nil
+	}
+	pb, e := FromStatus(err.Status())
+	if e != nil {
+		return nil, e
+	}
+	if withDebugInfo {
+		pb.DebugInfo = debugInfoOf(err)
+	}
+	return pb, nil
+}
+
+func debugInfoOf(err error) *DebugInfo {
+	info := &DebugInfo{}
+	for e := err; domainerr.NotNil(e); e = errors.UnwrapOnce(e) {
+		c := &Cause{
+			Type:    fmt.Sprintf("%T", e),
+			Message: e.Error(),
+		}
+		if de, ok := e.(*domainerr.Error); ok {
+			c.Message = de.Status().Message()
+		}
+		if stp, ok := e.(errors.StackTraceProvider); ok {
+			for _, frame := range stp.StackTrace() {
+				fn := runtime.FuncForPC(uintptr(frame) - 1)
+				if fn == nil {
+					continue
+				}
+				file, line := fn.FileLine(uintptr(frame) - 1)
+				c.StackFrames = append(c.StackFrames, fmt.Sprintf("%s %s:%d", fn.Name(), file, line))
+			}
+		}
+		info.CauseChain = append(info.CauseChain, c)
+	}
+	if fields := domainerr.FieldsOf(err); len(fields) > 0 {
+		info.Fields = &structpb.Struct{Fields: make(map[string]*structpb.Value, len(fields))}
+		for _, f := range fields {
+			v, e := structpb.NewValue(f.Value)
+			if e != nil {
+				v = structpb.NewStringValue(fmt.Sprint(f.Value))
+			}
+			info.Fields.Fields[f.Key] = v
+		}
+	}
+	return info
+}
+
+// ToStatus converts this wire format back to a status. The case is restored with
+// domainerr.RestoreCaseHierarchy from reg. Details packed from proto messages are unpacked as they are,
+// and details encoded as google.protobuf.Value are decoded into Go values like json.Unmarshal
+// does. A single detail is restored as the details, and multiple ones are restored as an []any.
+func (x *Status) ToStatus(reg domainerr.CaseRegistry) (*domainerr.Status, error) {
+	s := domainerr.NewWithCodeValue(int(x.GetCode())).WithMessage(x.GetMessage())
+	if x.GetCaseId() != "" {
+		s = s.WithCase(domainerr.RestoreCaseHierarchy(reg, x.GetCaseId(), s.Code(), x.GetCaseAncestors()))
+	}
+
+	details := make([]any, 0, len(x.GetDetails()))
+	for _, a := range x.GetDetails() {
+		m, err := a.UnmarshalNew()
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack details %s: %w", a.GetTypeUrl(), err)
+		}
+		if v, ok := m.(*structpb.Value); ok {
+			details = append(details, v.AsInterface())
+		} else {
+			details = append(details, m)
+		}
+	}
+	switch len(details) {
+	case 0:
+	case 1:
+		s = s.WithDetails(details[0])
+	default:
+		s = s.WithDetails(details)
+	}
+	return s, nil
+}
+
+// Marshal encodes the given *domainerr.Error in the wire format. See FromError.
+func Marshal(err *domainerr.Error, withDebugInfo bool) ([]byte, error) {
+	pb, e := FromError(err, withDebugInfo)
+	if e != nil {
+		return nil, e
+	}
+	return proto.Marshal(pb)
+}
+
+// Unmarshal decodes a status from the given wire format. See ToStatus.
+func Unmarshal(b []byte, reg domainerr.CaseRegistry) (*domainerr.Status, error) {
+	pb := &Status{}
+	if err := proto.Unmarshal(b, pb); err != nil {
+		return nil, err
+	}
+	return pb.ToStatus(reg)
+}
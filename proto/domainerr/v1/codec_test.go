@@ -0,0 +1,166 @@
+package domainerrv1
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type testCase string
+
+func (c testCase) Identifier() string {
+	return string(c)
+}
+
+func (c testCase) StatusCode() domainerr.Code {
+	return domainerr.CodeFailedPrecondition
+}
+
+const purchaseLimitExceeded = testCase("order.purchase_limit_exceeded")
+
+func TestFromStatus(t *testing.T) {
+	pb, err := FromStatus(domainerr.StatusFailedPrecondition.WithCaseAndMsg(purchaseLimitExceeded, "limit exceeded").
+		WithDetails(map[string]any{"limit": 10}))
+	assert.Nil(t, err)
+	assert.Equal(t, int32(9), pb.Code)
+	assert.Equal(t, "order.purchase_limit_exceeded", pb.CaseId)
+	assert.Equal(t, "limit exceeded", pb.Message)
+	assert.Len(t, pb.Details, 1)
+	assert.Equal(t, "type.googleapis.com/google.protobuf.Value", pb.Details[0].TypeUrl)
+	assert.Nil(t, pb.DebugInfo)
+
+	_, err = FromStatus(domainerr.StatusInternal.WithDetails(func() {}))
+	assert.NotNil(t, err)
+}
+
+func TestStatus_ToStatus(t *testing.T) {
+	original := domainerr.StatusFailedPrecondition.WithCaseAndMsg(purchaseLimitExceeded, "limit exceeded").
+		WithDetails(map[string]any{"limit": 10})
+	pb, _ := FromStatus(original)
+
+	s, err := pb.ToStatus(domainerr.NewMapCaseRegistry(purchaseLimitExceeded))
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.CodeFailedPrecondition, s.Code())
+	assert.Equal(t, purchaseLimitExceeded, s.SpecificCase())
+	assert.Equal(t, "limit exceeded", s.Message())
+	assert.Equal(t, map[string]any{"limit": 10.0}, s.Details())
+
+	s, err = pb.ToStatus(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "order.purchase_limit_exceeded", s.SpecificCase().Identifier())
+
+	pb.Details = append(pb.Details, pb.Details[0])
+	s, err = pb.ToStatus(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{map[string]any{"limit": 10.0}, map[string]any{"limit": 10.0}}, s.Details())
+}
+
+func TestStatus_MultipleDetails(t *testing.T) {
+	delay := durationpb.New(3e9)
+	original := domainerr.WithTypedDetail(domainerr.WithTypedDetail(domainerr.StatusResourceExhausted,
+		map[string]any{"limit": 10}), delay)
+	pb, err := FromStatus(original)
+	assert.Nil(t, err)
+	assert.Len(t, pb.Details, 2)
+
+	s, err := pb.ToStatus(nil)
+	assert.Nil(t, err)
+	restored, ok := domainerr.DetailOf[*durationpb.Duration](s)
+	assert.True(t, ok)
+	assert.True(t, proto.Equal(delay, restored))
+	limit, ok := domainerr.DetailOf[map[string]any](s)
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, limit["limit"])
+}
+
+func TestStatus_CaseAncestors(t *testing.T) {
+	insufficientFunds := domainerr.RestoreCaseHierarchy(nil, "payment.declined.insufficient_funds",
+		domainerr.CodeFailedPrecondition, []string{"payment.declined"})
+	pb, _ := FromStatus(domainerr.StatusFailedPrecondition.WithCase(insufficientFunds))
+	assert.Equal(t, []string{"payment.declined"}, pb.CaseAncestors)
+
+	s, err := pb.ToStatus(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"payment.declined"}, domainerr.AncestorIDs(s.SpecificCase()))
+
+	pb, _ = FromStatus(domainerr.StatusFailedPrecondition.WithCase(purchaseLimitExceeded))
+	assert.Empty(t, pb.CaseAncestors)
+}
+
+func TestMarshal_Unmarshal(t *testing.T) {
+	details := durationpb.New(3e9)
+	e := domainerr.NewAuthorizationExpired().WithMessage("log in again").WithDetails(details).Build()
+	b, err := Marshal(e, false)
+	assert.Nil(t, err)
+
+	s, err := Unmarshal(b, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, domainerr.CodeAuthorizationExpired, s.Code())
+	assert.Equal(t, "log in again", s.Message())
+	assert.Nil(t, s.SpecificCase())
+	assert.True(t, proto.Equal(details, s.Details().(proto.Message)))
+
+	_, err = Unmarshal([]byte{0xff}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestFromError_DebugInfo(t *testing.T) {
+	var nilErr *domainerr.Error
+	pb, err := FromError(nilErr, true)
+	assert.Nil(t, pb)
+	assert.Nil(t, err)
+
+	e := domainerr.NewInternalError().WithMessage("failed to save order").
+		WithCause(fmt.Errorf("db error")).Build()
+	pb, err = FromError(e, true)
+	assert.Nil(t, err)
+	chain := pb.DebugInfo.CauseChain
+	assert.Len(t, chain, 2)
+	assert.Equal(t, "*domainerr.Error", chain[0].Type)
+	assert.Equal(t, "failed to save order", chain[0].Message)
+	assert.True(t, strings.HasPrefix(chain[0].StackFrames[0],
+		"github.com/ikonglong/domainerr/proto/domainerr/v1.TestFromError_DebugInfo "), chain[0].StackFrames[0])
+	assert.Equal(t, "*errors.errorString", chain[1].Type)
+	assert.Equal(t, "db error", chain[1].Message)
+	assert.Empty(t, chain[1].StackFrames)
+
+	b, _ := Marshal(e, true)
+	decoded := &Status{}
+	assert.Nil(t, proto.Unmarshal(b, decoded))
+	assert.True(t, proto.Equal(pb, decoded))
+	assert.Nil(t, pb.DebugInfo.Fields)
+}
+
+func TestFromError_DebugInfoFields(t *testing.T) {
+	inner := domainerr.NewNotFound().Build().With("order_id", 42).With("at", struct{ X int }{1})
+	e := domainerr.NewInternalError().WithCause(inner).Build().With("user_id", "u1")
+
+	pb, err := FromError(e, true)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{"order_id": 42.0, "at": "{1}", "user_id": "u1"}, pb.DebugInfo.Fields.AsMap())
+
+	pb, _ = FromError(e, false)
+	assert.Nil(t, pb.DebugInfo)
+}
+
+type deprecatedCase struct {
+	testCase
+}
+
+func (c deprecatedCase) Lifecycle() domainerr.Lifecycle {
+	return domainerr.Lifecycle{Deprecated: true, Replacement: purchaseLimitExceeded}
+}
+
+func TestStatus_CaseDeprecation(t *testing.T) {
+	pb, _ := FromStatus(domainerr.StatusFailedPrecondition.WithCase(deprecatedCase{"order.limit_exceeded"}))
+	assert.Equal(t, "case order.limit_exceeded is deprecated, use order.purchase_limit_exceeded instead",
+		pb.CaseDeprecation)
+
+	pb, _ = FromStatus(domainerr.StatusFailedPrecondition.WithCase(purchaseLimitExceeded))
+	assert.Empty(t, pb.CaseDeprecation)
+}
@@ -0,0 +1,156 @@
+package quota
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a Limiter with a bucket of tokens per subject, which holds at most limit tokens
+// and is refilled evenly with limit tokens per window. So a subject may burst up to limit requests.
+type TokenBucket struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a TokenBucket allowing limit requests per window to each subject.
+func NewTokenBucket(limit int, window time.Duration, opts ...Opt) (*TokenBucket, error) {
+	o, err := newOptions(limit, window, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenBucket{
+		limit:     limit,
+		window:    window,
+		now:       o.now,
+		buckets:   make(map[string]*bucket),
+		lastSweep: o.now(),
+	}, nil
+}
+
+func (l *TokenBucket) Take(subject string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	b, found := l.buckets[subject]
+	if !found {
+		b = &bucket{tokens: float64(l.limit), last: now}
+		l.buckets[subject] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+
+	r := Result{Subject: subject, Limit: l.limit, Window: l.window}
+	if b.tokens >= 1 {
+		b.tokens--
+		r.Allowed = true
+	} else {
+		r.RetryAfter = l.timeFor(1 - b.tokens)
+	}
+	r.Remaining = int(b.tokens)
+	r.Reset = l.timeFor(float64(l.limit) - b.tokens)
+	return r
+}
+
+// refill returns the tokens of b at now.
+func (l *TokenBucket) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return b.tokens
+	}
+	return math.Min(float64(l.limit), b.tokens+float64(l.limit)*float64(elapsed)/float64(l.window))
+}
+
+// timeFor returns the time to refill the given number of tokens.
+func (l *TokenBucket) timeFor(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(l.window) / float64(l.limit)))
+}
+
+// sweep forgets the subjects whose buckets are full once per window, which are the same as new
+// ones. It must be called with the lock held.
+func (l *TokenBucket) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for subject, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.limit) {
+			delete(l.buckets, subject)
+		}
+	}
+	l.lastSweep = now
+}
+
+// FixedWindow is a Limiter counting the requests of each subject in fixed windows aligned to the
+// Unix epoch, e.g., [12:00, 12:01) for a window of 1m. It is cheaper than TokenBucket, but allows
+// up to twice the limit around the boundaries of windows.
+type FixedWindow struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+// NewFixedWindow creates a FixedWindow allowing limit requests per window to each subject.
+func NewFixedWindow(limit int, window time.Duration, opts ...Opt) (*FixedWindow, error) {
+	o, err := newOptions(limit, window, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &FixedWindow{
+		limit:  limit,
+		window: window,
+		now:    o.now,
+		counts: make(map[string]int),
+	}, nil
+}
+
+func (l *FixedWindow) Take(subject string) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if start := l.windowStart(now); !start.Equal(l.start) {
+		l.start = start
+		l.counts = make(map[string]int)
+	}
+	r := Result{
+		Subject: subject,
+		Limit:   l.limit,
+		Window:  l.window,
+		Reset:   l.start.Add(l.window).Sub(now),
+	}
+	if count := l.counts[subject]; count < l.limit {
+		l.counts[subject] = count + 1
+		r.Allowed = true
+	} else {
+		r.RetryAfter = r.Reset
+	}
+	r.Remaining = l.limit - l.counts[subject]
+	return r
+}
+
+// windowStart returns the start of the window containing now. Unlike time.Time.Truncate, which is
+// relative to the zero time, it is aligned to the Unix epoch.
+func (l *FixedWindow) windowStart(now time.Time) time.Time {
+	nanos := now.UnixNano()
+	offset := nanos % int64(l.window)
+	if offset < 0 {
+		offset += int64(l.window)
+	}
+	return time.Unix(0, nanos-offset)
+}
@@ -0,0 +1,99 @@
+package quota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ikonglong/domainerr/internal/fakeclock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	clock := fakeclock.New()
+	l, err := NewTokenBucket(3, 3*time.Second, WithClock(clock.Now))
+	assert.Nil(t, err)
+
+	for i := 2; i >= 0; i-- {
+		r := l.Take("user:1")
+		assert.True(t, r.Allowed)
+		assert.Equal(t, i, r.Remaining)
+	}
+	r := l.Take("user:1")
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+	assert.Equal(t, time.Second, r.RetryAfter)
+	assert.Equal(t, 3*time.Second, r.Reset)
+
+	// Other subjects have their own buckets.
+	assert.True(t, l.Take("user:2").Allowed)
+
+	clock.Advance(500 * time.Millisecond)
+	r = l.Take("user:1")
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 500*time.Millisecond, r.RetryAfter)
+
+	clock.Advance(500 * time.Millisecond)
+	r = l.Take("user:1")
+	assert.True(t, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+	assert.Equal(t, time.Duration(0), r.RetryAfter)
+
+	// Full buckets are forgotten.
+	clock.Advance(3 * time.Second)
+	assert.True(t, l.Take("user:3").Allowed)
+	assert.Len(t, l.buckets, 1)
+}
+
+func TestFixedWindow(t *testing.T) {
+	clock := fakeclock.New()
+	clock.Set(clock.Now().Truncate(time.Minute).Add(50 * time.Second))
+	l, err := NewFixedWindow(2, time.Minute, WithClock(clock.Now))
+	assert.Nil(t, err)
+
+	r := l.Take("user:1")
+	assert.True(t, r.Allowed)
+	assert.Equal(t, 1, r.Remaining)
+	assert.Equal(t, 10*time.Second, r.Reset)
+	assert.True(t, l.Take("user:1").Allowed)
+	r = l.Take("user:1")
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+	assert.Equal(t, 10*time.Second, r.RetryAfter)
+	assert.True(t, l.Take("user:2").Allowed)
+
+	clock.Advance(10 * time.Second)
+	r = l.Take("user:1")
+	assert.True(t, r.Allowed)
+	assert.Equal(t, 1, r.Remaining)
+	assert.Equal(t, time.Minute, r.Reset)
+}
+
+func TestFixedWindow_AlignedToUnixEpoch(t *testing.T) {
+	// 7s doesn't divide the time between the zero time and the Unix epoch.
+	clock := fakeclock.New()
+	clock.Set(time.Unix(700, 0).Add(2 * time.Second))
+	l, err := NewFixedWindow(1, 7*time.Second, WithClock(clock.Now))
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, l.Take("user:1").Reset)
+	assert.Equal(t, time.Unix(-7, 0), l.windowStart(time.Unix(-1, 0)))
+}
+
+func TestNewLimiters_IllegalArgs(t *testing.T) {
+	args := []struct {
+		limit  int
+		window time.Duration
+		opts   []Opt
+	}{
+		{0, time.Second, nil},
+		{1, 0, nil},
+		{1, time.Second, []Opt{WithClock(nil)}},
+	}
+	for _, a := range args {
+		tb, err := NewTokenBucket(a.limit, a.window, a.opts...)
+		assert.Nil(t, tb)
+		assert.NotNil(t, err)
+		fw, err := NewFixedWindow(a.limit, a.window, a.opts...)
+		assert.Nil(t, fw)
+		assert.NotNil(t, err)
+	}
+}
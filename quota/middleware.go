@@ -0,0 +1,65 @@
+package quota
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// HTTPMiddleware limits the requests to next with l. The subject of a request is given by
+// subjectOf, e.g., the client IP or the API key. The RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers are set on every response. A rejected request is replied with the
+// Retry-After header and the error of its Result written by writeErr. If writeErr is nil, the
+// error is replied with 429 and its status string as the body.
+func HTTPMiddleware(l Limiter, subjectOf func(r *http.Request) string, next http.Handler,
+	writeErr func(http.ResponseWriter, *http.Request, error)) http.Handler {
+	if writeErr == nil {
+		writeErr = writeError
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := l.Take(subjectOf(r))
+		SetHeaders(w.Header(), res)
+		if err := res.Err(); err != nil {
+			SetRetryAfter(w.Header(), err)
+			writeErr(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SetHeaders sets the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers from the
+// given Result. The reset is in seconds, rounded up.
+func SetHeaders(h http.Header, r Result) {
+	h.Set("RateLimit-Limit", strconv.Itoa(r.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(r.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(seconds(r.Reset)))
+}
+
+// SetRetryAfter sets the Retry-After header in seconds, rounded up, from the errdetails.RetryInfo
+// of the given error, e.g., the one returned by Allow. It does nothing if the error has none.
+func SetRetryAfter(h http.Header, err error) {
+	info, found := domainerr.FindDetail[*errdetails.RetryInfo](err)
+	if !found || info.GetRetryDelay() == nil {
+		return
+	}
+	h.Set("Retry-After", strconv.Itoa(seconds(info.GetRetryDelay().AsDuration())))
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func writeError(w http.ResponseWriter, _ *http.Request, err error) {
+	status := domainerr.StatusResourceExhausted
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) && domainerr.NotNil(domainErr) {
+		status = domainErr.Status()
+	}
+	http.Error(w, status.String(), http.StatusTooManyRequests)
+}
@@ -0,0 +1,48 @@
+package quota
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/domainerr/internal/fakeclock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	clock := fakeclock.New()
+	clock.Set(clock.Now().Truncate(time.Minute).Add(30500 * time.Millisecond))
+	l, _ := NewFixedWindow(1, time.Minute, WithClock(clock.Now))
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := HTTPMiddleware(l, func(r *http.Request) string { return r.Header.Get("X-API-Key") }, next, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-API-Key", "key1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Empty(t, w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "quota exceeded for key1")
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	h := http.Header{}
+	SetRetryAfter(h, Result{Subject: "key1", Limit: 1, Window: time.Minute, RetryAfter: 1500 * time.Millisecond}.Err())
+	assert.Equal(t, "2", h.Get("Retry-After"))
+
+	h = http.Header{}
+	SetRetryAfter(h, nil)
+	assert.Empty(t, h.Get("Retry-After"))
+}
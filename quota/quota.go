@@ -0,0 +1,140 @@
+// Package quota provides in-process rate limiters whose rejections are ResourceExhausted errors
+// carrying errdetails.QuotaFailure and errdetails.RetryInfo, and an HTTP middleware turning them
+// into Retry-After and RateLimit-* headers.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorInfoDomain and ErrorInfoReason identify the errdetails.ErrorInfo returned by Result.Err.
+const (
+	ErrorInfoDomain = "domainerr.quota"
+	ErrorInfoReason = "QUOTA_EXCEEDED"
+)
+
+// The keys of the metadata of the errdetails.ErrorInfo returned by Result.Err.
+const (
+	MetadataSubject = "subject"
+	MetadataLimit   = "limit"
+	// MetadataWindow is the window formatted by time.Duration.String, e.g., "1m0s".
+	MetadataWindow = "window"
+)
+
+// Limiter limits the requests of subjects, e.g., users or API keys. Implementations must be safe
+// for concurrent use.
+type Limiter interface {
+	// Take takes a request of the given subject from its quota.
+	Take(subject string) Result
+}
+
+// Result is the result of taking a request from a quota.
+type Result struct {
+	Subject string
+	// Allowed tells if the request is allowed.
+	Allowed bool
+	// Limit is the number of requests allowed in Window.
+	Limit  int
+	Window time.Duration
+	// Remaining is the number of requests still allowed now.
+	Remaining int
+	// Reset is the time until the quota is fully restored.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, which is 0 if Allowed.
+	RetryAfter time.Duration
+}
+
+// Err returns nil if the request is allowed. Otherwise, it returns a StatusResourceExhausted
+// *domainerr.Error, whose details are an errdetails.QuotaFailure describing the subject, the limit
+// and the window, an errdetails.ErrorInfo holding them as metadata, which can be read by LimitOf,
+// and an errdetails.RetryInfo with RetryAfter as the retry delay.
+func (r Result) Err() error {
+	if r.Allowed {
+		return nil
+	}
+	desc := fmt.Sprintf("%d requests per %s", r.Limit, r.Window)
+	s := domainerr.StatusResourceExhausted.WithMessagef("quota exceeded for %s: %s", r.Subject, desc)
+	s = domainerr.WithTypedDetail(s, &errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{Subject: r.Subject, Description: desc}},
+	})
+	s = domainerr.WithTypedDetail(s, &errdetails.ErrorInfo{
+		Domain: ErrorInfoDomain,
+		Reason: ErrorInfoReason,
+		Metadata: map[string]string{
+			MetadataSubject: r.Subject,
+			MetadataLimit:   strconv.Itoa(r.Limit),
+			MetadataWindow:  r.Window.String(),
+		},
+	})
+	s = domainerr.WithTypedDetail(s, &errdetails.RetryInfo{RetryDelay: durationpb.New(r.RetryAfter)})
+	return domainerr.NewWithStatus(s).Build()
+}
+
+// LimitOf returns the limit and the window of the quota exceeded, read from the
+// errdetails.ErrorInfo of the outermost *domainerr.Error in the cause chain of err, e.g., the one
+// returned by Allow.
+func LimitOf(err error) (limit int, window time.Duration, found bool) {
+	var domainErr *domainerr.Error
+	if !errors.As(err, &domainErr) || domainerr.IsNil(domainErr) {
+		return 0, 0, false
+	}
+	for _, info := range domainerr.DetailsOf[*errdetails.ErrorInfo](domainErr.Status()) {
+		if info.GetDomain() != ErrorInfoDomain {
+			continue
+		}
+		limit, err := strconv.Atoi(info.GetMetadata()[MetadataLimit])
+		if err != nil {
+			return 0, 0, false
+		}
+		window, err := time.ParseDuration(info.GetMetadata()[MetadataWindow])
+		if err != nil {
+			return 0, 0, false
+		}
+		return limit, window, true
+	}
+	return 0, 0, false
+}
+
+// Allow takes a request of the given subject from l, and returns the error documented in
+// Result.Err if it isn't allowed.
+func Allow(l Limiter, subject string) error {
+	return l.Take(subject).Err()
+}
+
+type options struct {
+	now func() time.Time
+}
+
+type Opt func(o *options)
+
+// WithClock sets the function returning the current time, which is time.Now by default. It lets
+// tests control the time.
+func WithClock(now func() time.Time) Opt {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+func newOptions(limit int, window time.Duration, opts []Opt) (options, error) {
+	o := options{now: time.Now}
+	for _, setOpt := range opts {
+		setOpt(&o)
+	}
+	err := domainerr.CheckArgument(limit > 0, "limit <= 0")
+	if err != nil {
+		return o, err
+	}
+	err = domainerr.CheckArgument(window > 0, "window <= 0")
+	if err != nil {
+		return o, err
+	}
+	err = domainerr.CheckArgument(o.now != nil, "clock is nil")
+	return o, err
+}
@@ -0,0 +1,55 @@
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func TestResult_Err(t *testing.T) {
+	assert.Nil(t, Result{Allowed: true}.Err())
+
+	err := Result{Subject: "user:1", Limit: 10, Window: time.Minute, RetryAfter: 6 * time.Second}.Err()
+	var de *domainerr.Error
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, domainerr.CodeResourceExhausted, de.Status().Code())
+	assert.Equal(t, "quota exceeded for user:1: 10 requests per 1m0s", de.Status().Message())
+
+	failure, found := domainerr.DetailsAs[*errdetails.QuotaFailure](err)
+	assert.True(t, found)
+	assert.Equal(t, "user:1", failure.GetViolations()[0].GetSubject())
+	assert.Equal(t, "10 requests per 1m0s", failure.GetViolations()[0].GetDescription())
+	info, found := domainerr.DetailsAs[*errdetails.RetryInfo](err)
+	assert.True(t, found)
+	assert.Equal(t, 6*time.Second, info.GetRetryDelay().AsDuration())
+
+	errInfo, found := domainerr.DetailsAs[*errdetails.ErrorInfo](err)
+	assert.True(t, found)
+	assert.Equal(t, ErrorInfoReason, errInfo.GetReason())
+	assert.Equal(t, "user:1", errInfo.GetMetadata()[MetadataSubject])
+	limit, window, found := LimitOf(fmt.Errorf("wrapped: %w", err))
+	assert.True(t, found)
+	assert.Equal(t, 10, limit)
+	assert.Equal(t, time.Minute, window)
+}
+
+func TestLimitOf_NotFound(t *testing.T) {
+	_, _, found := LimitOf(nil)
+	assert.False(t, found)
+	_, _, found = LimitOf(domainerr.NewResourceExhausted().Build())
+	assert.False(t, found)
+	_, _, found = LimitOf(errors.New("boom"))
+	assert.False(t, found)
+}
+
+func TestAllow(t *testing.T) {
+	l, err := NewFixedWindow(1, time.Minute)
+	assert.Nil(t, err)
+	assert.Nil(t, Allow(l, "user:1"))
+	assert.NotNil(t, Allow(l, "user:1"))
+}
@@ -0,0 +1,119 @@
+package domainerr
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// Backoff returns the delay before the given retry attempt, which starts from 1.
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff returns a Backoff which doubles the delay from base for each attempt, and caps
+// it at maxDelay.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < maxDelay; i++ {
+			d *= 2
+		}
+		if d > maxDelay {
+			d = maxDelay
+		}
+		return d
+	}
+}
+
+// RetryExecutor executes calls and retries the failed ones following the RetryAdvice of the status
+// of the returned *Error. Only JustRetryFailingCall errors are retried, because the other advices
+// mean that the failing call itself shouldn't be retried.
+type RetryExecutor struct {
+	maxAttempts int
+	backoff     Backoff
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+type RetryOpt func(r *RetryExecutor)
+
+// WithBackoff sets the Backoff of a RetryExecutor. The default is ExponentialBackoff(time.Second,
+// 30*time.Second), according to the advice for StatusUnavailable.
+func WithBackoff(b Backoff) RetryOpt {
+	return func(r *RetryExecutor) {
+		r.backoff = b
+	}
+}
+
+// NewRetryExecutor creates a RetryExecutor which calls at most maxAttempts times.
+func NewRetryExecutor(maxAttempts int, opts ...RetryOpt) (*RetryExecutor, error) {
+	err := CheckArgument(maxAttempts > 0, "maxAttempts <= 0")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &RetryExecutor{
+		maxAttempts: maxAttempts,
+		backoff:     ExponentialBackoff(time.Second, 30*time.Second),
+		sleep:       sleepCtx,
+	}
+	for _, setOpt := range opts {
+		setOpt(r)
+	}
+	err = CheckArgument(r.backoff != nil, "backoff is nil")
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Do executes call, and retries it while it returns an *Error advised to JustRetryFailingCall. The
+// delay before a retry is given by the Backoff, or by the errdetails.RetryInfo of the error if the
+// server asks for a longer one. It returns the error of the last attempt, or the error of ctx if
+// ctx is done while waiting.
+func (r *RetryExecutor) Do(ctx context.Context, call func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = call(ctx)
+		if err == nil || attempt >= r.maxAttempts || !IsRetryable(err) {
+			return err
+		}
+		delay := r.backoff(attempt)
+		if serverDelay, found := retryDelayOf(err); found && serverDelay > delay {
+			delay = serverDelay
+		}
+		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
+
+// IsRetryable tells if the failing call returning the given error can be just retried, i.e., the
+// error is or wraps an *Error advised to JustRetryFailingCall.
+func IsRetryable(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) || IsNil(e) {
+		return false
+	}
+	return e.Status().RetryAdvice() == JustRetryFailingCall
+}
+
+// retryDelayOf returns the retry delay in the errdetails.RetryInfo of the given error.
+func retryDelayOf(err error) (time.Duration, bool) {
+	info, found := FindDetail[*errdetails.RetryInfo](err)
+	if !found || info.GetRetryDelay() == nil {
+		return 0, false
+	}
+	return info.GetRetryDelay().AsDuration(), true
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
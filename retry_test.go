@@ -0,0 +1,117 @@
+package domainerr
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(time.Second, 5*time.Second)
+	assert.Equal(t, time.Second, b(1))
+	assert.Equal(t, 2*time.Second, b(2))
+	assert.Equal(t, 4*time.Second, b(3))
+	assert.Equal(t, 5*time.Second, b(4))
+	assert.Equal(t, 5*time.Second, b(100))
+}
+
+func TestNewRetryExecutor_IllegalArgument(t *testing.T) {
+	_, err := NewRetryExecutor(0)
+	assert.EqualError(t, err, "illegal argument: maxAttempts <= 0")
+	_, err = NewRetryExecutor(1, WithBackoff(nil))
+	assert.EqualError(t, err, "illegal argument: backoff is nil")
+}
+
+func newTestRetryExecutor(maxAttempts int) (*RetryExecutor, *[]time.Duration) {
+	r, _ := NewRetryExecutor(maxAttempts, WithBackoff(ExponentialBackoff(time.Second, time.Minute)))
+	var slept []time.Duration
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return ctx.Err()
+	}
+	return r, &slept
+}
+
+func TestRetryExecutor_Do(t *testing.T) {
+	r, slept := newTestRetryExecutor(3)
+	calls := 0
+	err := r.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return NewUnavailable().Build()
+		}
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *slept)
+}
+
+func TestRetryExecutor_Do_ServerRetryDelay(t *testing.T) {
+	r, slept := newTestRetryExecutor(4)
+	delays := []time.Duration{5 * time.Second, 500 * time.Millisecond}
+	calls := 0
+	err := r.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls > len(delays) {
+			return NewUnavailable().Build()
+		}
+		return NewUnavailable().WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delays[calls-1])}).Build()
+	})
+	assert.NotNil(t, err)
+	// The longer of the backoff and the server's delay is used.
+	assert.Equal(t, []time.Duration{5 * time.Second, 2 * time.Second, 4 * time.Second}, *slept)
+}
+
+func TestRetryExecutor_Do_MaxAttempts(t *testing.T) {
+	r, slept := newTestRetryExecutor(2)
+	calls := 0
+	unavailable := NewUnavailable().Build()
+	err := r.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return unavailable
+	})
+	assert.Equal(t, unavailable, err)
+	assert.Equal(t, 2, calls)
+	assert.Len(t, *slept, 1)
+}
+
+func TestRetryExecutor_Do_NotRetryable(t *testing.T) {
+	for _, e := range []error{
+		NewAborted().Build(), NewResourceExhausted().Build(), NewFailedPrecondition().Build(),
+		NewInternalError().Build(), fmt.Errorf("plain error"),
+	} {
+		r, slept := newTestRetryExecutor(3)
+		calls := 0
+		err := r.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return e
+		})
+		assert.Equal(t, e, err)
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, *slept)
+	}
+}
+
+func TestRetryExecutor_Do_ContextDone(t *testing.T) {
+	r, _ := newTestRetryExecutor(3)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := r.Do(ctx, func(ctx context.Context) error {
+		return NewUnavailable().Build()
+	})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestIsRetryable(t *testing.T) {
+	var nilErr *Error
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(nilErr))
+	assert.True(t, IsRetryable(fmt.Errorf("wrapped: %w", NewUnavailable().Build())))
+	assert.False(t, IsRetryable(NewAborted().Build()))
+}
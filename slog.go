@@ -0,0 +1,32 @@
+//go:build go1.21
+
+package domainerr
+
+import (
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer. It logs the error as a group of the code, the case, the
+// message and the fields merged by FieldsOf, e.g.,
+//
+//	slog.Error("failed to place order", "err", err)
+//
+// logs `err.code=NotFound err.case=order.not_found err.message=... err.fields.order_id=42` with the
+// text handler.
+func (e *Error) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 4)
+	code := e.status.Code()
+	attrs = append(attrs, slog.String("code", code.Name()))
+	if c := e.status.SpecificCase(); NotNil(c) {
+		attrs = append(attrs, slog.String("case", c.Identifier()))
+	}
+	attrs = append(attrs, slog.String("message", e.status.Message()))
+	if fields := FieldsOf(e); len(fields) > 0 {
+		fieldAttrs := make([]any, len(fields))
+		for i, f := range fields {
+			fieldAttrs[i] = slog.Any(f.Key, f.Value)
+		}
+		attrs = append(attrs, slog.Group("fields", fieldAttrs...))
+	}
+	return slog.GroupValue(attrs...)
+}
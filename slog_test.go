@@ -0,0 +1,35 @@
+//go:build go1.21
+
+package domainerr
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	inner := NewNotFound().WithMessage("order not found").With("order_id", 42).Build()
+	err := NewInternalError().WithCause(fmt.Errorf("wrapped: %w", inner)).Build().With("user_id", "u1")
+	logger.Error("failed", "err", err)
+	assert.Equal(t, "level=ERROR msg=failed err.code=InternalError err.message=\"\" "+
+		"err.fields.order_id=42 err.fields.user_id=u1\n", buf.String())
+
+	buf.Reset()
+	logger.Info("not found", "err", NewNotFound().WithSpecificCase(&treeCase4Test{identifier: "order.not_found"}).Build())
+	assert.Equal(t, "level=INFO msg=\"not found\" err.code=NotFound err.case=order.not_found err.message=\"\"\n",
+		buf.String())
+}
@@ -0,0 +1,184 @@
+package domainerr
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// StackFormatter formats an error and its cause chain with stack traces, like what `%+v` prints,
+// but filters and shortens the frames to make the output readable.
+//
+// The global StackFormatter is used when an Error is formatted with `%#v`. A StackFormatter can
+// also be used per call:
+//
+//	f := domainerr.NewStackFormatter(domainerr.TrimStdlibFrames(), domainerr.CollapseCauseFrames())
+//	log.Print(f.Sprint(err))
+type StackFormatter struct {
+	trimStdlib    bool
+	dropPrefixes  []string
+	moduleRoot    string
+	collapseCause bool
+}
+
+type StackFormatterOpt func(f *StackFormatter)
+
+// TrimStdlibFrames drops the frames of the runtime and the standard library, e.g.,
+// runtime.goexit, testing.tRunner and net/http.(*conn).serve.
+func TrimStdlibFrames() StackFormatterOpt {
+	return func(f *StackFormatter) {
+		f.trimStdlib = true
+	}
+}
+
+// DropFramesWithPrefix drops the frames whose function names start with any of the given prefixes,
+// e.g., "github.com/gin-gonic/gin.".
+func DropFramesWithPrefix(prefixes ...string) StackFormatterOpt {
+	return func(f *StackFormatter) {
+		f.dropPrefixes = append(f.dropPrefixes, prefixes...)
+	}
+}
+
+// RelativeToModuleRoot shows the source file paths under the given root directory relative to it.
+func RelativeToModuleRoot(root string) StackFormatterOpt {
+	return func(f *StackFormatter) {
+		if root != "" {
+			f.moduleRoot = strings.TrimSuffix(root, "/") + "/"
+		}
+	}
+}
+
+// CollapseCauseFrames omits the outermost frames of a cause which are the same as the ones of the
+// error wrapping it, and prints "... N more" instead.
+func CollapseCauseFrames() StackFormatterOpt {
+	return func(f *StackFormatter) {
+		f.collapseCause = true
+	}
+}
+
+func NewStackFormatter(opts ...StackFormatterOpt) *StackFormatter {
+	f := &StackFormatter{}
+	for _, setOpt := range opts {
+		setOpt(f)
+	}
+	return f
+}
+
+// Sprint formats the given error chain as a string.
+func (f *StackFormatter) Sprint(err error) string {
+	var sb strings.Builder
+	f.Fprint(&sb, err)
+	return sb.String()
+}
+
+// Fprint formats the given error chain and writes to w.
+func (f *StackFormatter) Fprint(w io.Writer, err error) {
+	var outerStack errors.StackTrace
+	for isOutermost := true; NotNil(err); isOutermost = false {
+		if isOutermost {
+			fmt.Fprintf(w, "\nerror occurred: %s", err.Error())
+		} else {
+			fmt.Fprintf(w, "\ncaused by: %s", err.Error())
+		}
+
+		if stp, ok := err.(errors.StackTraceProvider); ok {
+			st := stp.StackTrace()
+			numCommon := 0
+			if f.collapseCause {
+				numCommon = numCommonOutermostFrames(st, outerStack)
+			}
+			for _, frame := range st[:len(st)-numCommon] {
+				f.writeFrame(w, frame)
+			}
+			if numCommon > 0 {
+				fmt.Fprintf(w, "\n\t... %d more", numCommon)
+			}
+			if len(st) > 0 {
+				outerStack = st
+			}
+		}
+		err = errors.UnwrapOnce(err)
+	}
+}
+
+func (f *StackFormatter) writeFrame(w io.Writer, frame errors.Frame) {
+	fn := runtime.FuncForPC(uintptr(frame) - 1)
+	if fn == nil {
+		return
+	}
+	name := fn.Name()
+	file, line := fn.FileLine(uintptr(frame) - 1)
+	if f.trimStdlib && isStdlibFile(file) {
+		return
+	}
+	for _, prefix := range f.dropPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return
+		}
+	}
+	file = strings.TrimPrefix(file, f.moduleRoot)
+	fmt.Fprintf(w, "\n%s\n\t%s:%d", name, file, line)
+}
+
+// stdlibSrcDir is the directory of the source files of the standard library, as it appears in
+// the file paths of frames. It is derived from the file of a runtime function rather than
+// runtime.GOROOT, so that it also works for binaries built with -trimpath or moved to another
+// machine.
+var stdlibSrcDir = func() string {
+	fn := runtime.FuncForPC(reflect.ValueOf(runtime.Goexit).Pointer())
+	if fn == nil {
+		return ""
+	}
+	file, _ := fn.FileLine(fn.Entry())
+	if i := strings.LastIndex(file, "/runtime/"); i >= 0 {
+		return file[:i+1]
+	}
+	return ""
+}()
+
+// isStdlibFile tells if the given source file of a frame belongs to the standard library. Module
+// paths without a dot, e.g., myapp/internal/x, aren't mistaken for the standard library, because
+// their files aren't under the source directory of the standard library.
+func isStdlibFile(file string) bool {
+	return stdlibSrcDir != "" && strings.HasPrefix(file, stdlibSrcDir)
+}
+
+func numCommonOutermostFrames(st, outer errors.StackTrace) int {
+	n := 0
+	for i, j := len(st)-1, len(outer)-1; i >= 0 && j >= 0 && st[i] == outer[j]; i, j = i-1, j-1 {
+		n++
+	}
+	return n
+}
+
+// PrettyStackFormatter trims the stdlib frames and collapses the frames of causes. It is the
+// initial global StackFormatter.
+var PrettyStackFormatter = NewStackFormatter(TrimStdlibFrames(), CollapseCauseFrames())
+
+var globalStackFormatter = struct {
+	sync.RWMutex
+	f *StackFormatter
+}{f: PrettyStackFormatter}
+
+// SetStackFormatter sets the global StackFormatter used by `%#v`. A nil f restores
+// PrettyStackFormatter.
+func SetStackFormatter(f *StackFormatter) {
+	if f == nil {
+		f = PrettyStackFormatter
+	}
+	globalStackFormatter.Lock()
+	defer globalStackFormatter.Unlock()
+	globalStackFormatter.f = f
+}
+
+// GlobalStackFormatter returns the global StackFormatter used by `%#v`.
+func GlobalStackFormatter() *StackFormatter {
+	globalStackFormatter.RLock()
+	defer globalStackFormatter.RUnlock()
+	return globalStackFormatter.f
+}
@@ -0,0 +1,110 @@
+package domainerr
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackFormatter_NoOption(t *testing.T) {
+	err := NewInternalError().WithMessage("internal error").Build()
+	want := `
+error occurred: .+
+github.com/ikonglong/domainerr.TestStackFormatter_NoOption
+	.+/stack_formatter_test.go:\d+
+testing.tRunner
+	.+/testing/testing.go:\d+
+runtime.goexit
+	.+/runtime/asm_.+.s:\d+`
+	testTextRegexp(t, want, NewStackFormatter().Sprint(err))
+}
+
+func TestStackFormatter_TrimStdlibFrames_RelativeToModuleRoot(t *testing.T) {
+	err := NewInternalError().WithMessage("internal error").Build()
+	f := NewStackFormatter(TrimStdlibFrames(), RelativeToModuleRoot(moduleRoot()))
+	want := `
+error occurred: .+
+github.com/ikonglong/domainerr.TestStackFormatter_TrimStdlibFrames_RelativeToModuleRoot
+	stack_formatter_test.go:\d+$`
+	got := f.Sprint(err)
+	testTextRegexp(t, want, got)
+	assert.Equal(t, 4, len(strings.Split(got, "\n")))
+}
+
+func TestStackFormatter_DropFramesWithPrefix(t *testing.T) {
+	err := NewInternalError().WithMessage("internal error").Build()
+	f := NewStackFormatter(DropFramesWithPrefix("github.com/ikonglong/domainerr.", "testing."))
+	want := `
+error occurred: .+
+runtime.goexit
+	.+/runtime/asm_.+.s:\d+`
+	got := f.Sprint(err)
+	testTextRegexp(t, want, got)
+	assert.Equal(t, 4, len(strings.Split(got, "\n")))
+}
+
+func TestStackFormatter_CollapseCauseFrames(t *testing.T) {
+	app := application{
+		s: service{
+			repo: repository{
+				db: database{},
+			},
+		},
+	}
+	err := app.exec("test")
+
+	f := NewStackFormatter(TrimStdlibFrames(), CollapseCauseFrames(), RelativeToModuleRoot(moduleRoot()))
+	want := `
+error occurred: .+
+github.com/ikonglong/domainerr.service.exec
+	error_test.go:\d+
+github.com/ikonglong/domainerr.application.exec
+	error_test.go:\d+
+github.com/ikonglong/domainerr.TestStackFormatter_CollapseCauseFrames
+	stack_formatter_test.go:\d+
+caused by: network error
+github.com/ikonglong/domainerr.database.insert
+	error_test.go:\d+
+github.com/ikonglong/domainerr.repository.save
+	error_test.go:\d+
+github.com/ikonglong/domainerr.service.exec
+	error_test.go:\d+
+	\.\.\. 4 more$`
+	got := f.Sprint(err)
+	testTextRegexp(t, want, got)
+	assert.Equal(t, 16, len(strings.Split(got, "\n")))
+}
+
+func TestError_Format_SharpV(t *testing.T) {
+	defer SetStackFormatter(nil)
+
+	err := NewInternalError().WithMessage("internal error").Build()
+	assert.Equal(t, PrettyStackFormatter.Sprint(err), fmt.Sprintf("%#v", err))
+
+	f := NewStackFormatter(DropFramesWithPrefix("github.com/", "testing.", "runtime."))
+	SetStackFormatter(f)
+	assert.Equal(t, "\nerror occurred: "+err.Error(), fmt.Sprintf("%#v", err))
+}
+
+func TestIsStdlibFile(t *testing.T) {
+	for _, f := range []any{strings.HasPrefix, http.ListenAndServe, testing.Main} {
+		fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+		file, _ := fn.FileLine(fn.Entry())
+		assert.True(t, isStdlibFile(file), file)
+	}
+	_, file, _, _ := runtime.Caller(0)
+	assert.False(t, isStdlibFile(file))
+	assert.False(t, isStdlibFile("/home/dev/myapp/internal/x/x.go"))
+	assert.False(t, isStdlibFile("myapp/internal/x/x.go"))
+}
+
+func moduleRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
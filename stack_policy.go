@@ -0,0 +1,134 @@
+package domainerr
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// DefaultStackDepth is the max number of frames captured by FullStack.
+const DefaultStackDepth = 32
+
+// StackPolicy controls whether and how a stack trace is captured when an Error is built.
+//
+// Capturing a stack trace costs an allocation and a runtime.Callers call. For expected client
+// errors on hot paths (e.g., InvalidArgument returned by request validation) the stack is
+// rarely printed, so it can be disabled, capped or sampled.
+type StackPolicy struct {
+	maxDepth   int
+	sampleRate float64
+}
+
+// FullStack captures up to DefaultStackDepth frames for every error.
+func FullStack() StackPolicy {
+	return StackPolicy{maxDepth: DefaultStackDepth, sampleRate: 1}
+}
+
+// NoStack captures no stack trace.
+func NoStack() StackPolicy {
+	return StackPolicy{}
+}
+
+// StackWithDepth captures at most maxDepth frames for every error. A maxDepth <= 0 is the same
+// as NoStack.
+func StackWithDepth(maxDepth int) StackPolicy {
+	return StackPolicy{maxDepth: maxDepth, sampleRate: 1}
+}
+
+// Sampled returns a derived policy that captures a stack trace only for a fraction of errors.
+// The rate is clamped into [0, 1], e.g., 0.01 captures for about 1% of errors.
+func (p StackPolicy) Sampled(rate float64) StackPolicy {
+	if rate < 0 {
+		rate = 0
+	} else if rate > 1 {
+		rate = 1
+	}
+	p.sampleRate = rate
+	return p
+}
+
+// MaxDepth returns the max number of frames captured. Zero means no stack trace is captured.
+func (p StackPolicy) MaxDepth() int {
+	if p.maxDepth < 0 {
+		return 0
+	}
+	return p.maxDepth
+}
+
+// SampleRate returns the fraction of errors for which a stack trace is captured.
+func (p StackPolicy) SampleRate() float64 {
+	return p.sampleRate
+}
+
+// capture captures the stack trace of the caller according to this policy. The argument skip is
+// the number of stack frames to skip before recording, with 0 identifying the caller of capture.
+func (p StackPolicy) capture(skip int) *stack {
+	if p.maxDepth <= 0 || p.sampleRate <= 0 {
+		return emptyStack
+	}
+	if p.sampleRate < 1 && rand.Float64() >= p.sampleRate {
+		return emptyStack
+	}
+	pcs := make([]uintptr, p.maxDepth)
+	n := runtime.Callers(skip+2, pcs)
+	st := stack(pcs[:n])
+	return &st
+}
+
+// emptyStack is shared by all errors built without a stack trace. It is never mutated.
+var emptyStack = &stack{}
+
+var stackPolicies = struct {
+	sync.RWMutex
+	defaultPolicy StackPolicy
+	byCode        map[Code]StackPolicy
+}{
+	defaultPolicy: FullStack(),
+	byCode:        make(map[Code]StackPolicy),
+}
+
+// SetDefaultStackPolicy sets the policy used for codes without a specific policy. The initial
+// default is FullStack.
+func SetDefaultStackPolicy(p StackPolicy) {
+	stackPolicies.Lock()
+	defer stackPolicies.Unlock()
+	stackPolicies.defaultPolicy = p
+}
+
+// SetStackPolicyFor sets the policy used when building errors with the given status code. E.g.,
+//
+//	domainerr.SetStackPolicyFor(domainerr.CodeInvalidArgument, domainerr.NoStack())
+//	domainerr.SetStackPolicyFor(domainerr.CodeNotFound, domainerr.FullStack().Sampled(0.01))
+func SetStackPolicyFor(code Code, p StackPolicy) {
+	stackPolicies.Lock()
+	defer stackPolicies.Unlock()
+	stackPolicies.byCode[code] = p
+}
+
+// ResetStackPolicies removes all the per-code policies and restores the default to FullStack.
+func ResetStackPolicies() {
+	stackPolicies.Lock()
+	defer stackPolicies.Unlock()
+	stackPolicies.defaultPolicy = FullStack()
+	stackPolicies.byCode = make(map[Code]StackPolicy)
+}
+
+// StackPolicyFor returns the policy in effect for the given status code.
+func StackPolicyFor(code Code) StackPolicy {
+	stackPolicies.RLock()
+	defer stackPolicies.RUnlock()
+	if p, found := stackPolicies.byCode[code]; found {
+		return p
+	}
+	return stackPolicies.defaultPolicy
+}
+
+// stackPolicyOf returns the policy for the code of s, or the default policy if s is nil.
+func stackPolicyOf(s *Status) StackPolicy {
+	if s != nil {
+		return StackPolicyFor(s.code)
+	}
+	stackPolicies.RLock()
+	defer stackPolicies.RUnlock()
+	return stackPolicies.defaultPolicy
+}
@@ -0,0 +1,131 @@
+package domainerr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackPolicy_Defaults(t *testing.T) {
+	defer ResetStackPolicies()
+
+	assert.Equal(t, FullStack(), StackPolicyFor(CodeInvalidArgument))
+	e := NewInvalidArgument().Build()
+	assert.NotEmpty(t, e.StackTrace())
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestStackPolicy_Defaults", funcNameOf(e, 0))
+}
+
+func TestStackPolicy_NoStack(t *testing.T) {
+	defer ResetStackPolicies()
+
+	SetStackPolicyFor(CodeInvalidArgument, NoStack())
+	e := NewInvalidArgument().WithMessage("bad arg").Build()
+	assert.Empty(t, e.StackTrace())
+	assert.Equal(t, "\nerror occurred: "+e.Error(), fmt.Sprintf("%+v", e))
+
+	// other codes are not affected
+	assert.NotEmpty(t, NewInternalError().Build().StackTrace())
+}
+
+func TestStackPolicy_DefaultPolicy(t *testing.T) {
+	defer ResetStackPolicies()
+
+	SetDefaultStackPolicy(NoStack())
+	SetStackPolicyFor(CodeDataLoss, FullStack())
+	assert.Empty(t, NewNotFound().Build().StackTrace())
+	assert.Empty(t, NewError(StatusInternal).StackTrace())
+	assert.NotEmpty(t, NewDataLoss().Build().StackTrace())
+}
+
+func TestStackPolicy_MaxDepth(t *testing.T) {
+	defer ResetStackPolicies()
+
+	SetDefaultStackPolicy(StackWithDepth(1))
+	e := NewInternalError().Build()
+	assert.Len(t, e.StackTrace(), 1)
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestStackPolicy_MaxDepth", funcNameOf(e, 0))
+
+	assert.Equal(t, 0, StackWithDepth(-1).MaxDepth())
+	assert.Empty(t, NewInternalError().WithStackPolicy(StackWithDepth(-1)).Build().StackTrace())
+}
+
+func TestStackPolicy_Sampled(t *testing.T) {
+	assert.Equal(t, 0.0, FullStack().Sampled(-1).SampleRate())
+	assert.Equal(t, 1.0, FullStack().Sampled(2).SampleRate())
+
+	never := NewInternalError().WithStackPolicy(FullStack().Sampled(0)).Build()
+	assert.Empty(t, never.StackTrace())
+	always := NewInternalError().WithStackPolicy(FullStack().Sampled(1)).Build()
+	assert.NotEmpty(t, always.StackTrace())
+
+	captured := 0
+	for i := 0; i < 1000; i++ {
+		if len(NewInternalError().WithStackPolicy(FullStack().Sampled(0.5)).Build().StackTrace()) > 0 {
+			captured++
+		}
+	}
+	assert.True(t, captured > 0 && captured < 1000, "captured %d of 1000", captured)
+}
+
+func TestErrorBuilder_WithStackPolicy(t *testing.T) {
+	defer ResetStackPolicies()
+
+	SetStackPolicyFor(CodeInternalError, NoStack())
+	e := NewInternalError().WithStackPolicy(FullStack()).Build()
+	assert.NotEmpty(t, e.StackTrace())
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestErrorBuilder_WithStackPolicy", funcNameOf(e, 0))
+}
+
+func funcNameOf(e *Error, frameIdx int) string {
+	frame := fmt.Sprintf("%+s", e.StackTrace()[frameIdx])
+	return strings.SplitN(frame, "\n", 2)[0]
+}
+
+func BenchmarkNewInvalidArgument_WithStack(b *testing.B) {
+	defer ResetStackPolicies()
+	SetStackPolicyFor(CodeInvalidArgument, FullStack())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewInvalidArgument().Build()
+	}
+}
+
+func BenchmarkNewInvalidArgument_WithoutStack(b *testing.B) {
+	defer ResetStackPolicies()
+	SetStackPolicyFor(CodeInvalidArgument, NoStack())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewInvalidArgument().Build()
+	}
+}
+
+func BenchmarkNewInvalidArgument_DepthOf8(b *testing.B) {
+	defer ResetStackPolicies()
+	SetStackPolicyFor(CodeInvalidArgument, StackWithDepth(8))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NewInvalidArgument().Build()
+	}
+}
+
+func TestErrorBuilder_WithCallerSkip(t *testing.T) {
+	newNotFound := func() *Error {
+		return NewNotFound().WithCallerSkip(1).Build()
+	}
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestErrorBuilder_WithCallerSkip",
+		funcNameOfTopFrame(newNotFound()))
+}
+
+func TestStackPolicy_NilStatus(t *testing.T) {
+	defer ResetStackPolicies()
+
+	assert.NotEmpty(t, NewWithStatus(nil).Build().StackTrace())
+	assert.NotEmpty(t, NewError(nil).StackTrace())
+	SetDefaultStackPolicy(NoStack())
+	assert.Empty(t, NewWithStatus(nil).Build().StackTrace())
+}
@@ -238,15 +238,17 @@ func NewByHTTPStatus(statusCode int) *Status {
 
 // NewWithCodeValue returns a copy of the status prototype mapped to given op status code.
 func NewWithCodeValue(codeValue int) *Status {
-	if codeValue < 0 || codeValue > CodeList[len(CodeList)-1].Value() {
-		return StatusUnknown.WithMessagef("Unknown op status code: %v", codeValue)
+	for i := range statusList {
+		if statusList[i].code.value == codeValue {
+			return statusList[i].copy()
+		}
 	}
-	return &statusList[codeValue]
+	return StatusUnknown.WithMessagef("Unknown op status code: %v", codeValue)
 }
 
 // NewWithCode returns a copy of the status prototype mapped to given op status code.
 func NewWithCode(code Code) *Status {
-	return &statusList[code.value]
+	return NewWithCodeValue(code.value)
 }
 
 // Status defines the status of an operation by providing a standard Code in conjunction with an
@@ -34,3 +34,21 @@ func (c *case4Test) Identifier() string {
 func (c *case4Test) StatusCode() Code {
 	return CodeFailedPrecondition
 }
+
+func TestNewWithCodeValue(t *testing.T) {
+	for _, code := range CodeList {
+		s := NewWithCodeValue(code.Value())
+		assert.Equal(t, code, s.Code())
+		assert.Equal(t, code, NewWithCode(code).Code())
+	}
+
+	s := NewWithCodeValue(CodeAuthorizationExpired.Value())
+	s.AugmentMessage("more context")
+	assert.Equal(t, "", NewWithCodeValue(CodeAuthorizationExpired.Value()).Message())
+
+	for _, v := range []int{-1, 17, 31} {
+		s = NewWithCodeValue(v)
+		assert.Equal(t, CodeUnknown, s.Code())
+		assert.Equal(t, fmt.Sprintf("Unknown op status code: %d", v), s.Message())
+	}
+}
@@ -0,0 +1,160 @@
+package translate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ikonglong/domainerr"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the config of a Translator, which can be loaded from JSON or YAML, e.g.,
+//
+//	rules:
+//	  - from: {code: NotFound, case: "user.*"}
+//	    to: {case: order.customer_missing}
+//	defaults:
+//	  InternalError: {code: ServiceUnavailable}
+//	fallback: {code: UnknownError, inheritMessage: true}
+//
+// The status codes are referred to by their names, i.e., Code.Name(), and the target cases by
+// their identifiers, which are looked up in a domainerr.CaseRegistry.
+type Config struct {
+	Rules    []RuleConfig            `json:"rules" yaml:"rules"`
+	Defaults map[string]TargetConfig `json:"defaults" yaml:"defaults"`
+	Fallback *TargetConfig           `json:"fallback" yaml:"fallback"`
+}
+
+type RuleConfig struct {
+	From MatchConfig  `json:"from" yaml:"from"`
+	To   TargetConfig `json:"to" yaml:"to"`
+}
+
+type MatchConfig struct {
+	Code string `json:"code" yaml:"code"`
+	Case string `json:"case" yaml:"case"`
+}
+
+type TargetConfig struct {
+	Code           string `json:"code" yaml:"code"`
+	Case           string `json:"case" yaml:"case"`
+	Message        string `json:"message" yaml:"message"`
+	InheritMessage bool   `json:"inheritMessage" yaml:"inheritMessage"`
+}
+
+// ConfigError reports all the problems found when building a Translator from a Config.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid translator config: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ConfigError) addf(format string, args ...any) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// Build validates this config and creates the Translator, resolving the target cases from reg.
+// All the problems found are reported together in a *ConfigError.
+func (c *Config) Build(reg domainerr.CaseRegistry) (*Translator, error) {
+	cerr := &ConfigError{}
+	var opts []TranslatorOpt
+	for i, r := range c.Rules {
+		where := fmt.Sprintf("rule %d", i)
+		from, ok1 := r.From.build(where, cerr)
+		to, ok2 := r.To.build(where, reg, cerr)
+		if ok1 && ok2 {
+			opts = append(opts, WithRule(from, to))
+		}
+	}
+
+	names := make([]string, 0, len(c.Defaults))
+	for name := range c.Defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		where := "default for " + name
+		code, found := domainerr.CodeByName(name)
+		if !found {
+			cerr.addf("%s: unknown status code %q", where, name)
+		}
+		if to, ok := c.Defaults[name].build(where, reg, cerr); ok && found {
+			opts = append(opts, WithDefault(code, to))
+		}
+	}
+
+	if c.Fallback != nil {
+		if to, ok := c.Fallback.build("fallback", reg, cerr); ok {
+			opts = append(opts, WithFallback(to))
+		}
+	}
+
+	if len(cerr.Problems) > 0 {
+		return nil, cerr
+	}
+	return NewTranslator(opts...)
+}
+
+func (c MatchConfig) build(where string, cerr *ConfigError) (Match, bool) {
+	m := Match{CasePattern: c.Case}
+	if c.Code != "" {
+		code, found := domainerr.CodeByName(c.Code)
+		if !found {
+			cerr.addf("%s: unknown status code %q", where, c.Code)
+			return m, false
+		}
+		m.Code = code
+	}
+	return m, true
+}
+
+func (c TargetConfig) build(where string, reg domainerr.CaseRegistry, cerr *ConfigError) (Target, bool) {
+	t := Target{Message: c.Message, InheritMessage: c.InheritMessage}
+	switch {
+	case c.Case != "" && c.Code != "":
+		cerr.addf("%s: target has both a case and a status code", where)
+		return t, false
+	case c.Case != "":
+		var found bool
+		if reg != nil {
+			t.Case, found = reg.Lookup(c.Case)
+		}
+		if !found {
+			cerr.addf("%s: unknown case %q", where, c.Case)
+			return t, false
+		}
+	case c.Code != "":
+		code, found := domainerr.CodeByName(c.Code)
+		if !found {
+			cerr.addf("%s: unknown status code %q", where, c.Code)
+			return t, false
+		}
+		t.Code = code
+	default:
+		cerr.addf("%s: target has neither a case nor a status code", where)
+		return t, false
+	}
+	return t, true
+}
+
+// LoadJSON creates a Translator from a Config in JSON. See Config.Build.
+func LoadJSON(data []byte, reg domainerr.CaseRegistry) (*Translator, error) {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode translator config: %w", err)
+	}
+	return c.Build(reg)
+}
+
+// LoadYAML creates a Translator from a Config in YAML. See Config.Build.
+func LoadYAML(data []byte, reg domainerr.CaseRegistry) (*Translator, error) {
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to decode translator config: %w", err)
+	}
+	return c.Build(reg)
+}
@@ -0,0 +1,189 @@
+// Package translate translates the errors of another bounded context, e.g., the ones returned by a
+// remote service, into the statuses and cases of the local one.
+package translate
+
+import (
+	"errors"
+	"path"
+
+	"github.com/ikonglong/domainerr"
+)
+
+// Match matches the statuses of errors to translate.
+type Match struct {
+	// Code matches the statuses with this status code. The zero Code matches any status code.
+	Code domainerr.Code
+	// CasePattern matches the identifiers of the specific cases with the syntax of path.Match,
+	// e.g., "user.*". An empty pattern matches any status, with or without a case.
+	CasePattern string
+}
+
+func (m Match) matches(s *domainerr.Status) bool {
+	if m.Code != (domainerr.Code{}) && m.Code != s.Code() {
+		return false
+	}
+	if m.CasePattern == "" {
+		return true
+	}
+	c := s.SpecificCase()
+	if domainerr.IsNil(c) {
+		return false
+	}
+	matched, _ := path.Match(m.CasePattern, c.Identifier())
+	return matched
+}
+
+// Target is the local status that errors are translated to.
+type Target struct {
+	// Case is the case of the local status, whose status code is used. It takes precedence over
+	// Code.
+	Case domainerr.Case
+	// Code is the status code of the local status if Case is nil.
+	Code domainerr.Code
+	// Message is the message of the local status. If it's empty, the message of the original
+	// status is used if InheritMessage is true.
+	Message        string
+	InheritMessage bool
+}
+
+func (t Target) status(original *domainerr.Status) *domainerr.Status {
+	var s *domainerr.Status
+	if domainerr.NotNil(t.Case) {
+		s = domainerr.NewWithCode(t.Case.StatusCode()).WithCase(t.Case)
+	} else {
+		s = domainerr.NewWithCode(t.Code)
+	}
+	switch {
+	case t.Message != "":
+		s = s.WithMessage(t.Message)
+	case t.InheritMessage:
+		s = s.WithMessage(original.Message())
+	}
+	return s
+}
+
+func (t Target) validate() error {
+	if domainerr.NotNil(t.Case) {
+		return nil
+	}
+	return domainerr.CheckArgument(t.Code != (domainerr.Code{}) && t.Code != domainerr.CodeOK,
+		"target has neither a case nor a status code other than OK")
+}
+
+// Rule translates the statuses matched by From to To.
+type Rule struct {
+	From Match
+	To   Target
+}
+
+// Translator translates errors by rules, which are tried in order. The statuses matched by no
+// rule are translated by the default of their status codes, or by the fallback if there is no
+// default. E.g., a caller of a user service may translate
+//
+//	user.* NotFound -> order.customer_missing
+//	default InternalError -> ServiceUnavailable
+//
+// It is safe for concurrent use.
+type Translator struct {
+	rules    []Rule
+	defaults map[domainerr.Code]Target
+	fallback *Target
+}
+
+type TranslatorOpt func(t *Translator) error
+
+// WithRule appends a rule translating the statuses matched by from to to.
+func WithRule(from Match, to Target) TranslatorOpt {
+	return func(t *Translator) error {
+		if err := to.validate(); err != nil {
+			return err
+		}
+		if from.CasePattern != "" {
+			_, err := path.Match(from.CasePattern, "")
+			err = domainerr.CheckArgument(err == nil, "case pattern %q is malformed", from.CasePattern)
+			if err != nil {
+				return err
+			}
+		}
+		t.rules = append(t.rules, Rule{From: from, To: to})
+		return nil
+	}
+}
+
+// WithDefault translates the statuses with the given status code to to, if no rule matches them.
+func WithDefault(code domainerr.Code, to Target) TranslatorOpt {
+	return func(t *Translator) error {
+		if err := to.validate(); err != nil {
+			return err
+		}
+		t.defaults[code] = to
+		return nil
+	}
+}
+
+// WithFallback translates the statuses matched by neither a rule nor a default to to. Without a
+// fallback, such errors are returned as they are.
+func WithFallback(to Target) TranslatorOpt {
+	return func(t *Translator) error {
+		if err := to.validate(); err != nil {
+			return err
+		}
+		t.fallback = &to
+		return nil
+	}
+}
+
+func NewTranslator(opts ...TranslatorOpt) (*Translator, error) {
+	t := &Translator{defaults: make(map[domainerr.Code]Target)}
+	for _, setOpt := range opts {
+		if err := setOpt(t); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// TranslateStatus translates the given status. It returns false if the status is matched by
+// neither a rule, a default nor the fallback.
+func (t *Translator) TranslateStatus(s *domainerr.Status) (*domainerr.Status, bool) {
+	if to, found := t.targetOf(s); found {
+		return to.status(s), true
+	}
+	return nil, false
+}
+
+// Translate translates the status of the outermost *domainerr.Error in the cause chain of err, and
+// returns a *domainerr.Error with the translated status caused by err, so that the original error
+// is kept. An error that isn't a *domainerr.Error is translated as an Unknown error. An error that
+// can't be translated is returned as it is, and so is a nil err. The stack trace of the returned
+// error starts at the caller of Translate.
+func (t *Translator) Translate(err error) error {
+	if domainerr.IsNil(err) {
+		return err
+	}
+	original := domainerr.StatusUnknown
+	var domainErr *domainerr.Error
+	if errors.As(err, &domainErr) && domainerr.NotNil(domainErr) {
+		original = domainErr.Status()
+	}
+	s, found := t.TranslateStatus(original)
+	if !found {
+		return err
+	}
+	return domainerr.NewWithStatus(s).WithCause(err).WithCallerSkip(1).Build()
+}
+
+func (t *Translator) targetOf(s *domainerr.Status) (Target, bool) {
+	for _, r := range t.rules {
+		if r.From.matches(s) {
+			return r.To, true
+		}
+	}
+	if to, found := t.defaults[s.Code()]; found {
+		return to, true
+	}
+	if t.fallback != nil {
+		return *t.fallback, true
+	}
+	return Target{}, false
+}
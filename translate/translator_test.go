@@ -0,0 +1,152 @@
+package translate
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ikonglong/domainerr"
+	"github.com/ikonglong/domainerr/namedcase"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	userFactory, _     = namedcase.NewFactory("user")
+	userNotFound, _    = userFactory.NewNotFound("user_not_found")
+	userLocked, _      = userFactory.NewFailedPrecondition("user_locked")
+	orderFactory, _    = namedcase.NewFactory("order")
+	customerMissing, _ = orderFactory.NewFailedPrecondition("customer_missing")
+)
+
+func newTestTranslator(t *testing.T) *Translator {
+	tr, err := NewTranslator(
+		WithRule(Match{Code: domainerr.CodeNotFound, CasePattern: "user.*"}, Target{Case: customerMissing}),
+		WithRule(Match{CasePattern: "user.user_locked"}, Target{Code: domainerr.CodePermissionDenied, InheritMessage: true}),
+		WithDefault(domainerr.CodeInternalError, Target{Code: domainerr.CodeUnavailable, Message: "user service is unavailable"}),
+		WithFallback(Target{Code: domainerr.CodeUnknown}),
+	)
+	assert.Nil(t, err)
+	return tr
+}
+
+func TestTranslator_TranslateStatus(t *testing.T) {
+	tr := newTestTranslator(t)
+	tests := []struct {
+		name     string
+		from     *domainerr.Status
+		wantCode domainerr.Code
+		wantCase domainerr.Case
+		wantMsg  string
+	}{
+		{"rule by code and case", domainerr.StatusNotFound.WithCase(userNotFound).WithMessage("user 1 not found"),
+			domainerr.CodeFailedPrecondition, customerMissing, ""},
+		{"rule by case", domainerr.StatusFailedPrecondition.WithCase(userLocked).WithMessage("user 1 is locked"),
+			domainerr.CodePermissionDenied, nil, "user 1 is locked"},
+		{"no case matches no case rule", domainerr.StatusNotFound,
+			domainerr.CodeUnknown, nil, ""},
+		{"default", domainerr.StatusInternal.WithMessage("db down"),
+			domainerr.CodeUnavailable, nil, "user service is unavailable"},
+		{"fallback", domainerr.StatusAborted,
+			domainerr.CodeUnknown, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, found := tr.TranslateStatus(tt.from)
+			assert.True(t, found)
+			assert.Equal(t, tt.wantCode, s.Code())
+			if tt.wantCase == nil {
+				assert.True(t, domainerr.IsNil(s.SpecificCase()))
+			} else {
+				assert.Equal(t, tt.wantCase, s.SpecificCase())
+			}
+			assert.Equal(t, tt.wantMsg, s.Message())
+		})
+	}
+}
+
+func TestTranslator_TranslateStatus_NoFallback(t *testing.T) {
+	tr, err := NewTranslator(WithDefault(domainerr.CodeInternalError, Target{Code: domainerr.CodeUnavailable}))
+	assert.Nil(t, err)
+	s, found := tr.TranslateStatus(domainerr.StatusNotFound)
+	assert.False(t, found)
+	assert.Nil(t, s)
+}
+
+func TestTranslator_Translate(t *testing.T) {
+	tr := newTestTranslator(t)
+	assert.Nil(t, tr.Translate(nil))
+
+	remote := domainerr.NewNotFound().WithSpecificCase(userNotFound).Build()
+	err := tr.Translate(remote)
+	var de *domainerr.Error
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, domainerr.CodeFailedPrecondition, de.Status().Code())
+	assert.Equal(t, customerMissing, de.Status().SpecificCase())
+	assert.Same(t, remote, de.Cause())
+	top := de.StackTrace()[0]
+	assert.Equal(t, "TestTranslator_Translate", fmt.Sprintf("%n", top))
+
+	plain := errors.New("connection reset")
+	err = tr.Translate(plain)
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, domainerr.CodeUnknown, de.Status().Code())
+	assert.True(t, errors.Is(err, plain))
+
+	noFallback, _ := NewTranslator()
+	assert.Same(t, remote, noFallback.Translate(remote))
+}
+
+func TestNewTranslator_IllegalArgs(t *testing.T) {
+	opts := []TranslatorOpt{
+		WithRule(Match{}, Target{}),
+		WithRule(Match{CasePattern: "user.["}, Target{Code: domainerr.CodeUnknown}),
+		WithDefault(domainerr.CodeInternalError, Target{Code: domainerr.CodeOK}),
+		WithFallback(Target{}),
+	}
+	for _, opt := range opts {
+		tr, err := NewTranslator(opt)
+		assert.Nil(t, tr)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	reg := domainerr.NewMapCaseRegistry(customerMissing)
+	tr, err := LoadYAML([]byte(`
+rules:
+  - from: {code: NotFound, case: "user.*"}
+    to: {case: order.customer_missing}
+defaults:
+  InternalError: {code: ServiceUnavailable, inheritMessage: true}
+fallback: {code: UnknownError}
+`), reg)
+	assert.Nil(t, err)
+
+	s, _ := tr.TranslateStatus(domainerr.StatusNotFound.WithCase(userNotFound))
+	assert.Equal(t, customerMissing, s.SpecificCase())
+	s, _ = tr.TranslateStatus(domainerr.StatusInternal.WithMessage("db down"))
+	assert.Equal(t, domainerr.CodeUnavailable, s.Code())
+	assert.Equal(t, "db down", s.Message())
+	s, _ = tr.TranslateStatus(domainerr.StatusAborted)
+	assert.Equal(t, domainerr.CodeUnknown, s.Code())
+}
+
+func TestLoadJSON_Invalid(t *testing.T) {
+	reg := domainerr.NewMapCaseRegistry(customerMissing)
+	_, err := LoadJSON([]byte(`{
+		"rules": [{"from": {"code": "NoSuchCode"}, "to": {"case": "order.no_such_case"}}],
+		"defaults": {"InternalError": {"code": "ServiceUnavailable", "case": "order.customer_missing"}},
+		"fallback": {}
+	}`), reg)
+	var cerr *ConfigError
+	assert.True(t, errors.As(err, &cerr))
+	assert.Equal(t, []string{
+		`rule 0: unknown status code "NoSuchCode"`,
+		`rule 0: unknown case "order.no_such_case"`,
+		"default for InternalError: target has both a case and a status code",
+		"fallback: target has neither a case nor a status code",
+	}, cerr.Problems)
+
+	_, err = LoadJSON([]byte(`{`), reg)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,110 @@
+package domainerr
+
+import (
+	"fmt"
+)
+
+type wrapOptions struct {
+	rewrap         bool
+	inheritMessage bool
+}
+
+type WrapOpt func(o *wrapOptions)
+
+// Rewrap makes Wrap and WrapCase replace the status of err if err is a *Error, instead of wrapping
+// it with a new *Error. The returned error keeps the cause, the fields and the stack trace of err,
+// so that a layer translating the status, e.g., from NotFound to FailedPrecondition, doesn't add
+// a stack trace pointing to itself. An err that isn't a *Error is wrapped as usual.
+func Rewrap() WrapOpt {
+	return func(o *wrapOptions) {
+		o.rewrap = true
+	}
+}
+
+// InheritMessage makes Wrap and WrapCase use the message of err as the message of the returned
+// error. The message of a *Error is the one of its status, and the one of another error is the
+// result of its Error method.
+func InheritMessage() WrapOpt {
+	return func(o *wrapOptions) {
+		o.inheritMessage = true
+	}
+}
+
+// Wrap returns an error with the given status caused by err, whose stack trace is captured at the
+// caller of Wrap according to the StackPolicy of the status code. It returns nil if err is nil, so
+// that it can be used in return statements directly:
+//
+//	row, err := db.QueryRow(...)
+//	if err != nil {
+//		return domainerr.Wrap(err, domainerr.StatusNotFound.WithMessage("order not found"))
+//	}
+//
+// The returned error is a *Error. If status is nil, an illegal argument error wrapping err is
+// returned instead.
+func Wrap(err error, status *Status, opts ...WrapOpt) error {
+	if IsNil(err) {
+		return nil
+	}
+	if IsNil(status) {
+		return CheckArgument(false, "status is nil, failed to wrap: %w", err)
+	}
+	return wrap(err, status, opts)
+}
+
+// Wrapf is like Wrap, but the message of the status is replaced by the formatted one.
+func Wrapf(err error, status *Status, msgFmt string, args ...any) error {
+	if IsNil(err) {
+		return nil
+	}
+	if IsNil(status) {
+		return CheckArgument(false, "status is nil, failed to wrap: %w", err)
+	}
+	return wrap(err, status.WithMessage(fmt.Sprintf(msgFmt, args...)), nil)
+}
+
+// WrapCase is like Wrap, but the status is derived from the given case, i.e., it has the status
+// code of the case. If c is nil, an illegal argument error wrapping err is returned instead.
+func WrapCase(err error, c Case, opts ...WrapOpt) error {
+	if IsNil(err) {
+		return nil
+	}
+	if IsNil(c) {
+		return CheckArgument(false, "case is nil, failed to wrap: %w", err)
+	}
+	return wrap(err, NewWithCode(c.StatusCode()).WithCase(c), opts)
+}
+
+// wrap must be called by the exported functions directly, so that the stack trace is captured at
+// their callers.
+func wrap(err error, status *Status, opts []WrapOpt) *Error {
+	var o wrapOptions
+	for _, setOpt := range opts {
+		setOpt(&o)
+	}
+
+	status = status.copy()
+	if o.inheritMessage {
+		status = status.WithMessage(messageOf(err))
+	}
+	warnIfDeprecated(status)
+	if de, ok := err.(*Error); ok && o.rewrap {
+		return &Error{
+			status: status,
+			cause:  de.cause,
+			fields: append([]Field(nil), de.fields...),
+			stack:  de.stack,
+		}
+	}
+	return &Error{
+		status: status,
+		cause:  err,
+		stack:  StackPolicyFor(status.code).capture(2),
+	}
+}
+
+func messageOf(err error) string {
+	if de, ok := err.(*Error); ok {
+		return de.status.Message()
+	}
+	return err.Error()
+}
@@ -0,0 +1,121 @@
+package domainerr
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// funcNameOfTopFrame returns the name of the function of the innermost frame of the stack of e.
+func funcNameOfTopFrame(e *Error) string {
+	st := e.StackTrace()
+	if len(st) == 0 {
+		return ""
+	}
+	return runtime.FuncForPC(uintptr(st[0]) - 1).Name()
+}
+
+func TestWrap_Nil(t *testing.T) {
+	assert.Nil(t, Wrap(nil, StatusNotFound))
+	assert.Nil(t, Wrapf(nil, StatusNotFound, "order %d not found", 42))
+	assert.Nil(t, WrapCase(nil, &treeCase4Test{identifier: "order.not_found"}))
+
+	var nilErr *Error
+	assert.Nil(t, Wrap(nilErr, StatusNotFound))
+
+	f := func() error {
+		return Wrap(nil, StatusNotFound)
+	}
+	assert.True(t, f() == nil)
+}
+
+func TestWrap_NilStatusOrCase(t *testing.T) {
+	cause := fmt.Errorf("no rows")
+	var nilCase *treeCase4Test
+	for _, err := range []error{
+		Wrap(cause, nil),
+		Wrapf(cause, nil, "order %d not found", 42),
+		WrapCase(cause, nil),
+		WrapCase(cause, nilCase),
+	} {
+		assert.Contains(t, err.Error(), "illegal argument: ")
+		assert.Contains(t, err.Error(), "is nil, failed to wrap: no rows")
+		assert.True(t, errors.Is(err, cause))
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cause := fmt.Errorf("sql: no rows in result set")
+	err := Wrap(cause, StatusNotFound.WithMessage("order not found"))
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, CodeNotFound, e.Status().Code())
+	assert.Equal(t, "order not found", e.Status().Message())
+	assert.Same(t, cause, e.Cause())
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestWrap", funcNameOfTopFrame(e))
+
+	// the status isn't shared
+	e.AugmentMessage("more")
+	assert.Equal(t, "", StatusNotFound.Message())
+}
+
+func TestWrapf(t *testing.T) {
+	err := Wrapf(fmt.Errorf("timeout"), StatusUnavailable, "inventory %s unavailable", "svc")
+	e := err.(*Error)
+	assert.Equal(t, CodeUnavailable, e.Status().Code())
+	assert.Equal(t, "inventory svc unavailable", e.Status().Message())
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestWrapf", funcNameOfTopFrame(e))
+}
+
+func TestWrapCase(t *testing.T) {
+	c := &treeCase4Test{identifier: "order.not_found"}
+	e := WrapCase(fmt.Errorf("no rows"), c).(*Error)
+	assert.Equal(t, c.StatusCode(), e.Status().Code())
+	assert.Same(t, c, e.Status().SpecificCase())
+	assert.Equal(t, "github.com/ikonglong/domainerr.TestWrapCase", funcNameOfTopFrame(e))
+}
+
+func TestWrap_InheritMessage(t *testing.T) {
+	e := Wrap(fmt.Errorf("connection refused"), StatusUnavailable, InheritMessage()).(*Error)
+	assert.Equal(t, "connection refused", e.Status().Message())
+
+	inner := NewNotFound().WithMessage("order not found").Build()
+	e = Wrap(inner, StatusFailedPrecondition, InheritMessage()).(*Error)
+	assert.Equal(t, "order not found", e.Status().Message())
+	assert.Same(t, inner, e.Cause())
+}
+
+func TestWrap_Rewrap(t *testing.T) {
+	root := fmt.Errorf("no rows")
+	inner := NewNotFound().WithMessage("order not found").WithCause(root).Build().With("order_id", 42)
+
+	e := Wrap(inner, StatusFailedPrecondition.WithMessage("order must exist"), Rewrap()).(*Error)
+	assert.Equal(t, CodeFailedPrecondition, e.Status().Code())
+	assert.Equal(t, "order must exist", e.Status().Message())
+	assert.Same(t, root, e.Cause())
+	assert.Equal(t, inner.StackTrace(), e.StackTrace())
+	assert.Equal(t, []Field{{"order_id", 42}}, e.Fields())
+
+	// the original is untouched
+	assert.Equal(t, CodeNotFound, inner.Status().Code())
+	e.fields[0].Value = 43
+	assert.Equal(t, []Field{{"order_id", 42}}, inner.Fields())
+
+	e = Wrap(inner, StatusFailedPrecondition, Rewrap(), InheritMessage()).(*Error)
+	assert.Equal(t, "order not found", e.Status().Message())
+
+	// an error that isn't a *Error is wrapped
+	e = Wrap(root, StatusNotFound, Rewrap()).(*Error)
+	assert.Same(t, root, e.Cause())
+}
+
+func TestWrap_StackPolicy(t *testing.T) {
+	SetStackPolicyFor(CodeNotFound, NoStack())
+	defer ResetStackPolicies()
+	e := Wrap(fmt.Errorf("no rows"), StatusNotFound).(*Error)
+	assert.Empty(t, e.StackTrace())
+}